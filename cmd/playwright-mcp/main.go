@@ -0,0 +1,268 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+// Command playwright-mcp runs the Playwright MCP server over stdio or HTTP.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/microsoft/playwright-mcp/internal/config"
+	"github.com/microsoft/playwright-mcp/internal/httpserver"
+	"github.com/microsoft/playwright-mcp/internal/mcp"
+	"github.com/microsoft/playwright-mcp/internal/server"
+	"github.com/microsoft/playwright-mcp/internal/telemetry"
+)
+
+const version = "0.0.1"
+
+func main() {
+	opts, printConfig := parseFlags()
+	cfg, err := config.Load(opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "playwright-mcp: %v\n", err)
+		os.Exit(1)
+	}
+
+	if printConfig {
+		data, err := json.MarshalIndent(cfg.Redacted(), "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "playwright-mcp: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	telemetryShutdown, err := telemetry.Init(context.Background(), version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "playwright-mcp: telemetry init failed: %v\n", err)
+	} else {
+		defer telemetryShutdown(context.Background())
+	}
+
+	srv, err := server.NewServer(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "playwright-mcp: %v\n", err)
+		os.Exit(1)
+	}
+	defer srv.Close()
+	defer func() {
+		// A panic on this goroutine must not leave managed browser
+		// processes behind. Linux has pdeathsig as a kernel-level backstop;
+		// elsewhere this is the only orphan protection.
+		if r := recover(); r != nil {
+			srv.Browsers().KillAll()
+			panic(r)
+		}
+	}()
+
+	if err := server.CleanupOutput(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "playwright-mcp: output cleanup failed: %v\n", err)
+	}
+	defer server.CleanupOutput(cfg)
+
+	if opts.Config != "" {
+		watchConfigReload(opts, srv)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+
+	if cfg.Server.Port != nil {
+		httpServer := httpserver.NewServer(cfg, srv)
+		go func() {
+			<-sigCh
+			httpServer.Shutdown(cfg.Server.ShutdownGrace.Std())
+		}()
+		if err := httpServer.ListenAndServe(); err != nil {
+			fmt.Fprintf(os.Stderr, "playwright-mcp: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	backend, err := srv.NewConnection(nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "playwright-mcp: %v\n", err)
+		os.Exit(1)
+	}
+	transport := mcp.NewStdioTransport(os.Stdin, os.Stdout, nil)
+	conn := mcp.NewConnection(transport, backend, serverInfo())
+	go func() {
+		<-sigCh
+		srv.Drain(cfg.Server.ShutdownGrace.Std())
+		conn.Close()
+	}()
+	if err := conn.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "playwright-mcp: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// watchConfigReload re-resolves the config on SIGHUP or when the config
+// file's mtime changes, and applies the runtime-tunable subset to the live
+// server without restarting it.
+func watchConfigReload(opts *config.CLIOptions, srv *server.Server) {
+	reload := func() {
+		next, err := config.Load(opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "playwright-mcp: config reload failed: %v\n", err)
+			return
+		}
+		srv.ApplyRuntimeConfig(next)
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			reload()
+		}
+	}()
+
+	go func() {
+		var lastModTime time.Time
+		if info, err := os.Stat(opts.Config); err == nil {
+			lastModTime = info.ModTime()
+		}
+		ticker := time.NewTicker(2 * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			info, err := os.Stat(opts.Config)
+			if err != nil {
+				continue
+			}
+			if info.ModTime().After(lastModTime) {
+				lastModTime = info.ModTime()
+				reload()
+			}
+		}
+	}()
+}
+
+func serverInfo() mcp.Implementation {
+	return mcp.Implementation{Name: "Playwright", Version: version}
+}
+
+// stringSliceFlag makes a flag repeatable: each occurrence appends a value.
+type stringSliceFlag []string
+
+func (f *stringSliceFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringSliceFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func parseFlags() (*config.CLIOptions, bool) {
+	opts := &config.CLIOptions{}
+	var capabilities string
+	var printConfig bool
+	flag.BoolVar(&printConfig, "print-config", false, "print the fully resolved configuration as JSON (secrets masked) and exit")
+	flag.StringVar(&opts.Browser, "browser", "", "browser or chrome channel to use (chromium, firefox, webkit, chrome, msedge, ...)")
+	flag.StringVar(&opts.Channel, "channel", "", "browser distribution channel")
+	flag.BoolVar(&opts.Headless, "headless", false, "run the browser headless")
+	flag.StringVar(&opts.ExecutablePath, "executable-path", "", "path to the browser executable")
+	flag.StringVar(&opts.UserDataDir, "user-data-dir", "", "path to the user data directory")
+	flag.StringVar(&opts.CDPEndpoint, "cdp-endpoint", "", "CDP endpoint of a running browser to connect to")
+	flag.StringVar(&opts.ConnectWS, "connect-ws", "", "remote Playwright server to drive, e.g. wss://grid.example.com")
+	flag.Var((*stringSliceFlag)(&opts.ConnectWSHeaders), "connect-ws-header", "header sent on the -connect-ws handshake, as \"Name: value\" (repeatable)")
+	flag.BoolVar(&opts.Extension, "extension", false, "drive the user's running Chrome via the companion extension")
+	flag.BoolVar(&opts.Isolated, "isolated", false, "keep the browser profile in memory")
+	flag.BoolVar(&opts.AutoInstall, "auto-install", false, "download the browser automatically if launching fails because it is not installed")
+	flag.BoolVar(&opts.ForceProfile, "force-profile", false, "if the profile is in use by another browser, launch against a temporary copy of it")
+	flag.StringVar(&opts.StorageState, "storage-state", "", "storage state file to seed isolated contexts with")
+	flag.BoolVar(&opts.SaveStorageState, "save-storage-state", false, "write the final storage state back to disk on close")
+	flag.StringVar(&opts.Device, "device", "", "device to emulate, e.g. \"iPhone 15\"")
+	var listDevices bool
+	flag.BoolVar(&listDevices, "list-devices", false, "print the known device names and exit")
+	flag.StringVar(&opts.ProxyServer, "proxy-server", "", "proxy server to use")
+	flag.Var((*stringSliceFlag)(&opts.InitScripts), "init-script", "JavaScript file to run in every page before its own scripts (repeatable)")
+	flag.Var((*stringSliceFlag)(&opts.Headers), "header", "extra HTTP header sent with every request, as \"Name: value\" (repeatable)")
+	flag.StringVar(&opts.Locale, "locale", "", "locale to emulate, e.g. de-DE")
+	flag.StringVar(&opts.Timezone, "timezone", "", "timezone to emulate, e.g. Europe/Berlin")
+	flag.StringVar(&opts.ColorScheme, "color-scheme", "", "color scheme to emulate: light, dark or no-preference")
+	flag.StringVar(&opts.Geolocation, "geolocation", "", "geolocation to emulate as \"lat,lng\", e.g. \"52.52,13.40\"")
+	flag.StringVar(&opts.HTTPCredentials, "http-credentials", "", "credentials for HTTP Basic/Digest auth as \"user:pass\"")
+	flag.StringVar(&opts.Host, "host", "", "host to bind the HTTP server to")
+	flag.IntVar(&opts.Port, "port", 0, "port to listen on for SSE/HTTP transport (0 = ephemeral)")
+	flag.StringVar(&opts.PortFile, "port-file", "", "write the resolved server URL to this file once listening")
+	flag.StringVar(&opts.AuthToken, "auth-token", "", "bearer token required on all HTTP endpoints (defaults to $PLAYWRIGHT_MCP_TOKEN)")
+	var wsOrigins string
+	flag.StringVar(&wsOrigins, "allowed-ws-origins", "", "comma-separated web origins allowed to open WebSocket connections (default: same host)")
+	flag.StringVar(&opts.Config, "config", "", "path to a configuration file")
+	flag.StringVar(&capabilities, "caps", "", "comma-separated list of capabilities to enable")
+	var allowedTools, blockedTools string
+	flag.StringVar(&allowedTools, "allowed-tools", "", "comma-separated tool names to expose (default: all)")
+	flag.StringVar(&blockedTools, "blocked-tools", "", "comma-separated tool names to hide; wins over -allowed-tools")
+	flag.StringVar(&opts.OutputDir, "output-dir", "", "directory for output files")
+	flag.BoolVar(&opts.SaveTrace, "save-trace", false, "save a Playwright trace of the session")
+	flag.BoolVar(&opts.SaveSession, "save-session", false, "write a log of every tool call to <output-dir>/session.md and session.jsonl")
+	flag.StringVar(&opts.SaveVideo, "save-video", "", "record page videos at the given resolution, e.g. \"800x600\"")
+	flag.BoolVar(&opts.SaveHar, "save-har", false, "record the session's network traffic to <output-dir>/session.har")
+	flag.StringVar(&opts.SaveHarContent, "save-har-content", "", "HAR response-body capture: embed, attach or omit (default embed)")
+	flag.StringVar(&opts.ReplayHar, "replay-har", "", "serve matching requests from this HAR file instead of the network")
+	flag.BoolVar(&opts.ReplayHarUpdate, "replay-har-update", false, "record missing entries into the -replay-har file instead of aborting them")
+	flag.IntVar(&opts.SnapshotMaxChars, "snapshot-max-chars", 0, "truncate page snapshots beyond this many characters (0 = unlimited); use browser_snapshot_expand for pruned subtrees")
+	var snapshotExcludeRoles, snapshotExcludeSelectors, snapshotExcludeAreas string
+	flag.StringVar(&snapshotExcludeRoles, "snapshot-exclude-roles", "", "comma-separated ARIA roles to drop from snapshots")
+	flag.StringVar(&snapshotExcludeSelectors, "snapshot-exclude-selectors", "", "comma-separated CSS selectors to drop from snapshots")
+	flag.StringVar(&snapshotExcludeAreas, "snapshot-exclude-areas", "", "comma-separated boilerplate areas to drop from snapshots: ads, cookie-banners, nav, footer, hidden")
+	flag.StringVar(&opts.Dialogs, "dialogs", "", "dialog policy: manual (default), auto-accept or auto-dismiss")
+	flag.StringVar(&opts.AllowedUploadDir, "allowed-upload-dir", "", "restrict browser_file_upload to files under this directory")
+	flag.StringVar(&opts.SecretsFile, "secrets-file", "", "file of name=value pairs; tools reference secrets by name and values are masked in all output")
+	flag.DurationVar(&opts.TimeoutAction, "timeout-action", 0, "timeout for page actions such as click or fill (default 5s)")
+	flag.DurationVar(&opts.TimeoutNavigation, "timeout-navigation", 0, "timeout for page navigations (default 60s)")
+	flag.DurationVar(&opts.ShutdownGrace, "shutdown-grace", 0, "how long in-flight tool calls may finish during shutdown (default 15s)")
+	flag.DurationVar(&opts.SessionIdleTimeout, "session-idle-timeout", 0, "close a session's browser context after this much inactivity (e.g. 10m)")
+	flag.IntVar(&opts.MaxSessions, "max-sessions", 0, "maximum number of concurrently open browser sessions (0 = unlimited)")
+	flag.IntVar(&opts.WarmContexts, "warm-contexts", 0, "keep this many pre-launched isolated contexts ready (requires -isolated)")
+	flag.StringVar(&opts.ContextMode, "context-mode", "", "shared gives all connections the same tabs, per-client isolates them (default per-client)")
+	flag.StringVar(&opts.PathPrefix, "path-prefix", "", "mount all HTTP routes under this prefix, e.g. /playwright")
+	flag.StringVar(&opts.LogFormat, "log-format", "", "log output format: json or console (default json)")
+	flag.Parse()
+	if listDevices {
+		for _, name := range config.DeviceNames() {
+			fmt.Println(name)
+		}
+		os.Exit(0)
+	}
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "port" {
+			opts.PortSet = true
+		}
+	})
+	if capabilities != "" {
+		opts.Capabilities = strings.Split(capabilities, ",")
+	}
+	if allowedTools != "" {
+		opts.AllowedTools = strings.Split(allowedTools, ",")
+	}
+	if blockedTools != "" {
+		opts.BlockedTools = strings.Split(blockedTools, ",")
+	}
+	if wsOrigins != "" {
+		opts.AllowedWSOrigins = strings.Split(wsOrigins, ",")
+	}
+	if snapshotExcludeRoles != "" {
+		opts.SnapshotExcludeRoles = strings.Split(snapshotExcludeRoles, ",")
+	}
+	if snapshotExcludeSelectors != "" {
+		opts.SnapshotExcludeSelectors = strings.Split(snapshotExcludeSelectors, ",")
+	}
+	if snapshotExcludeAreas != "" {
+		opts.SnapshotExcludeAreas = strings.Split(snapshotExcludeAreas, ",")
+	}
+	return opts, printConfig
+}
@@ -0,0 +1,14 @@
+module github.com/microsoft/playwright-mcp
+
+go 1.22
+
+require (
+	github.com/gorilla/websocket v1.5.3
+	github.com/playwright-community/playwright-go v0.4802.0
+	github.com/rs/zerolog v1.33.0
+	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.28.0
+	go.opentelemetry.io/otel/sdk v1.28.0
+	go.opentelemetry.io/otel/trace v1.28.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,138 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package browser
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// resolveChannelExecutable locates a system-installed browser for a channel
+// when Playwright's registry has nothing to offer, checking the usual
+// install locations for the current OS. It reports the executable path and
+// whether one was found.
+func resolveChannelExecutable(channel string) (string, bool) {
+	for _, candidate := range channelCandidates(channel) {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+func channelCandidates(channel string) []string {
+	switch runtime.GOOS {
+	case "darwin":
+		app, ok := darwinChannelApps[channel]
+		if !ok {
+			return nil
+		}
+		return []string{filepath.Join("/Applications", app+".app", "Contents", "MacOS", app)}
+	case "linux":
+		var candidates []string
+		for _, name := range linuxChannelBinaries[channel] {
+			if path, err := exec.LookPath(name); err == nil {
+				candidates = append(candidates, path)
+			}
+		}
+		candidates = append(candidates, linuxChannelPaths[channel]...)
+		return candidates
+	case "windows":
+		return windowsChannelCandidates(channel)
+	}
+	return nil
+}
+
+var darwinChannelApps = map[string]string{
+	"chrome":        "Google Chrome",
+	"chrome-beta":   "Google Chrome Beta",
+	"chrome-dev":    "Google Chrome Dev",
+	"chrome-canary": "Google Chrome Canary",
+	"msedge":        "Microsoft Edge",
+	"msedge-beta":   "Microsoft Edge Beta",
+	"msedge-dev":    "Microsoft Edge Dev",
+	"msedge-canary": "Microsoft Edge Canary",
+	"brave":         "Brave Browser",
+	"chromium":      "Chromium",
+}
+
+var linuxChannelBinaries = map[string][]string{
+	"chrome":      {"google-chrome", "google-chrome-stable"},
+	"chrome-beta": {"google-chrome-beta"},
+	"chrome-dev":  {"google-chrome-unstable"},
+	"msedge":      {"microsoft-edge", "microsoft-edge-stable"},
+	"msedge-beta": {"microsoft-edge-beta"},
+	"msedge-dev":  {"microsoft-edge-dev"},
+	"brave":       {"brave-browser"},
+	"chromium":    {"chromium", "chromium-browser"},
+}
+
+var linuxChannelPaths = map[string][]string{
+	"chrome":      {"/opt/google/chrome/chrome"},
+	"chrome-beta": {"/opt/google/chrome-beta/chrome"},
+	"chrome-dev":  {"/opt/google/chrome-unstable/chrome"},
+	"msedge":      {"/opt/microsoft/msedge/msedge"},
+	"msedge-beta": {"/opt/microsoft/msedge-beta/msedge"},
+	"msedge-dev":  {"/opt/microsoft/msedge-dev/msedge"},
+	"brave":       {"/opt/brave.com/brave/brave"},
+}
+
+// windowsChannelCandidates builds candidates from the standard install
+// roots, then falls back to the App Paths registry key, which covers
+// non-default install locations.
+func windowsChannelCandidates(channel string) []string {
+	suffix, ok := windowsChannelSuffixes[channel]
+	if !ok {
+		return nil
+	}
+	var candidates []string
+	for _, root := range []string{
+		os.Getenv("LOCALAPPDATA"),
+		os.Getenv("PROGRAMFILES"),
+		os.Getenv("PROGRAMFILES(X86)"),
+	} {
+		if root != "" {
+			candidates = append(candidates, filepath.Join(root, suffix))
+		}
+	}
+	if path, ok := windowsRegistryAppPath(filepath.Base(suffix)); ok {
+		candidates = append(candidates, path)
+	}
+	return candidates
+}
+
+var windowsChannelSuffixes = map[string]string{
+	"chrome":        filepath.Join("Google", "Chrome", "Application", "chrome.exe"),
+	"chrome-beta":   filepath.Join("Google", "Chrome Beta", "Application", "chrome.exe"),
+	"chrome-dev":    filepath.Join("Google", "Chrome Dev", "Application", "chrome.exe"),
+	"chrome-canary": filepath.Join("Google", "Chrome SxS", "Application", "chrome.exe"),
+	"msedge":        filepath.Join("Microsoft", "Edge", "Application", "msedge.exe"),
+	"msedge-beta":   filepath.Join("Microsoft", "Edge Beta", "Application", "msedge.exe"),
+	"msedge-dev":    filepath.Join("Microsoft", "Edge Dev", "Application", "msedge.exe"),
+	"msedge-canary": filepath.Join("Microsoft", "Edge SxS", "Application", "msedge.exe"),
+	"brave":         filepath.Join("BraveSoftware", "Brave-Browser", "Application", "brave.exe"),
+}
+
+// windowsRegistryAppPath queries HKLM App Paths for an executable via
+// reg.exe, avoiding a dependency on a registry package for one lookup.
+func windowsRegistryAppPath(executable string) (string, bool) {
+	key := `HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\App Paths\` + executable
+	output, err := exec.Command("reg", "query", key, "/ve").Output()
+	if err != nil {
+		return "", false
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		_, value, ok := strings.Cut(line, "REG_SZ")
+		if !ok {
+			continue
+		}
+		if path := strings.TrimSpace(value); path != "" {
+			return path, true
+		}
+	}
+	return "", false
+}
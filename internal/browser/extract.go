@@ -0,0 +1,178 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package browser
+
+import (
+	"fmt"
+)
+
+// extractMarkdownScript runs a readability-style extraction: it picks the
+// page's main content container, skips chrome (nav, header, footer, aside)
+// and converts the remaining structure to markdown with links, emphasis,
+// lists and code blocks preserved.
+const extractMarkdownScript = `(() => {
+  function pickRoot() {
+    const candidates = [
+      document.querySelector('article'),
+      document.querySelector('main'),
+      document.querySelector('[role="main"]'),
+    ].filter(Boolean);
+    for (const candidate of candidates) {
+      if ((candidate.textContent || '').trim().length > 200)
+        return candidate;
+    }
+    return document.body;
+  }
+
+  const skip = new Set([
+    'SCRIPT', 'STYLE', 'NOSCRIPT', 'TEMPLATE', 'IFRAME',
+    'NAV', 'HEADER', 'FOOTER', 'ASIDE',
+  ]);
+
+  function inline(node) {
+    let out = '';
+    for (const child of node.childNodes) {
+      if (child.nodeType === Node.TEXT_NODE) {
+        out += child.textContent.replace(/\s+/g, ' ');
+        continue;
+      }
+      if (child.nodeType !== Node.ELEMENT_NODE || skip.has(child.tagName))
+        continue;
+      switch (child.tagName) {
+        case 'A':
+          if (child.getAttribute('href')) {
+            out += '[' + inline(child).trim() + '](' + child.href + ')';
+            break;
+          }
+          out += inline(child);
+          break;
+        case 'STRONG': case 'B':
+          out += '**' + inline(child).trim() + '**';
+          break;
+        case 'EM': case 'I':
+          out += '*' + inline(child).trim() + '*';
+          break;
+        case 'CODE':
+          out += '` + "`" + `' + child.textContent + '` + "`" + `';
+          break;
+        case 'BR':
+          out += '\n';
+          break;
+        case 'IMG':
+          out += '![' + (child.getAttribute('alt') || '') + '](' + child.src + ')';
+          break;
+        default:
+          out += inline(child);
+      }
+    }
+    return out;
+  }
+
+  const blocks = [];
+  function renderList(list, depth) {
+    const ordered = list.tagName === 'OL';
+    let counter = 1;
+    for (const item of list.children) {
+      if (item.tagName !== 'LI')
+        continue;
+      const marker = ordered ? (counter++) + '. ' : '- ';
+      const text = inline(item).trim();
+      if (text)
+        blocks.push('  '.repeat(depth) + marker + text);
+      for (const sub of item.children) {
+        if (sub.tagName === 'UL' || sub.tagName === 'OL')
+          renderList(sub, depth + 1);
+      }
+    }
+  }
+  function visit(node) {
+    for (const child of node.children) {
+      if (skip.has(child.tagName))
+        continue;
+      const tag = child.tagName.toLowerCase();
+      if (/^h[1-6]$/.test(tag)) {
+        blocks.push('#'.repeat(+tag[1]) + ' ' + inline(child).trim());
+        continue;
+      }
+      if (tag === 'p') {
+        const text = inline(child).trim();
+        if (text)
+          blocks.push(text);
+        continue;
+      }
+      if (tag === 'ul' || tag === 'ol') {
+        renderList(child, 0);
+        continue;
+      }
+      if (tag === 'blockquote') {
+        const text = inline(child).trim();
+        if (text)
+          blocks.push('> ' + text);
+        continue;
+      }
+      if (tag === 'pre') {
+        blocks.push('` + "```" + `\n' + child.textContent.replace(/\n$/, '') + '\n` + "```" + `');
+        continue;
+      }
+      visit(child);
+    }
+  }
+  visit(pickRoot());
+  return blocks.join('\n\n');
+})()`
+
+// ExtractMarkdown returns the page's main content as markdown, with links
+// preserved.
+func (t *Tab) ExtractMarkdown() (string, error) {
+	value, err := t.page.Evaluate(extractMarkdownScript)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract page content: %w", err)
+	}
+	text, _ := value.(string)
+	return text, nil
+}
+
+// extractHTMLScript serializes the document (or a ref's subtree) to HTML,
+// optionally with scripts, styles and templates stripped.
+const extractHTMLScript = `(args) => {
+  let root = document.documentElement;
+  if (args.ref) {
+    const registry = window.__mcpRefs;
+    if (!registry)
+      throw new Error('no snapshot captured yet; call browser_snapshot first');
+    const element = registry.byRef.get(args.ref);
+    if (!element || !element.isConnected)
+      throw new Error('stale or unknown ref "' + args.ref + '"; capture a fresh snapshot');
+    root = element;
+  }
+  if (!args.strip)
+    return root.outerHTML;
+  const clone = root.cloneNode(true);
+  for (const node of clone.querySelectorAll('script, style, noscript, template'))
+    node.remove();
+  return clone.outerHTML;
+}`
+
+// HTML returns the page's outerHTML, or the subtree under ref when set.
+// strip removes script, style, noscript and template elements first.
+func (t *Tab) HTML(ref string, strip bool) (string, error) {
+	frame := t.page.MainFrame()
+	local := ref
+	if ref != "" {
+		if err := t.ResolveRef(ref); err != nil {
+			return "", err
+		}
+		var err error
+		frame, local, err = t.frameForRef(ref)
+		if err != nil {
+			return "", err
+		}
+	}
+	value, err := frame.Evaluate(extractHTMLScript, map[string]any{"ref": local, "strip": strip})
+	if err != nil {
+		return "", fmt.Errorf("failed to extract HTML: %w", err)
+	}
+	text, _ := value.(string)
+	return text, nil
+}
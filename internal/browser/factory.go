@@ -0,0 +1,151 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+// Package browser owns the Playwright runtime: launching browsers,
+// creating contexts and mapping the resolved config onto launch and
+// context options.
+package browser
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/playwright-community/playwright-go"
+
+	"github.com/microsoft/playwright-mcp/internal/config"
+)
+
+// ContextFactory creates the browser context behind one MCP session. A
+// factory owns whatever process or connection the contexts come from and
+// releases it on Close.
+type ContextFactory interface {
+	CreateContext() (playwright.BrowserContext, error)
+	Close() error
+}
+
+// NewContextFactory picks the factory matching the configured mode.
+func NewContextFactory(cfg *config.FullConfig) ContextFactory {
+	if cfg.Browser.Extension {
+		// Extension mode drives the user's running Chrome through the CDP
+		// relay on the server's own /cdp route; the HTTP server fills
+		// Browser.CDPEndpoint in once its listener is bound, before any
+		// session can reach a tool.
+		return &CDPBrowserContextFactory{config: cfg}
+	}
+	if cfg.Browser.ConnectWS != "" {
+		return &RemoteContextFactory{config: cfg}
+	}
+	if cfg.Browser.CDPEndpoint != "" {
+		return &CDPBrowserContextFactory{config: cfg}
+	}
+	if cfg.Browser.Isolated {
+		factory := ContextFactory(&IsolatedContextFactory{config: cfg})
+		if size := cfg.Server.WarmContexts; size > 0 {
+			factory = NewPooledContextFactory(factory, size)
+		}
+		return factory
+	}
+	return &PlaywrightContextFactory{config: cfg}
+}
+
+// browserTypeFor maps a browser name onto the Playwright browser type.
+func browserTypeFor(pw *playwright.Playwright, name string) (playwright.BrowserType, error) {
+	switch name {
+	case "", "chromium":
+		return pw.Chromium, nil
+	case "firefox":
+		return pw.Firefox, nil
+	case "webkit":
+		return pw.WebKit, nil
+	}
+	return nil, fmt.Errorf("unknown browser %q", name)
+}
+
+// CDPBrowserContextFactory connects to an already running browser over the
+// Chrome DevTools Protocol instead of launching one. Both ws:// and
+// http://host:port endpoints are accepted; Playwright resolves the latter
+// via /json/version itself.
+type CDPBrowserContextFactory struct {
+	config *config.FullConfig
+
+	mu      sync.Mutex
+	pw      *playwright.Playwright
+	browser playwright.Browser
+}
+
+// ensureBrowser connects on first use and reconnects after the remote
+// browser went away, so a restarted browser doesn't require restarting the
+// MCP server.
+func (f *CDPBrowserContextFactory) ensureBrowser() (playwright.Browser, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if name := f.config.Browser.BrowserName; name != "" && name != "chromium" {
+		return nil, fmt.Errorf("cdp endpoints require chromium, got %q", name)
+	}
+	if f.browser != nil && f.browser.IsConnected() {
+		return f.browser, nil
+	}
+	if f.pw == nil {
+		pw, err := playwright.Run()
+		if err != nil {
+			return nil, fmt.Errorf("failed to start playwright: %w", err)
+		}
+		f.pw = pw
+	}
+	var options []playwright.BrowserTypeConnectOverCDPOptions
+	if f.config.Browser.Extension && f.config.Server.AuthToken != "" {
+		// The relay sits behind the server's own bearer-token auth; only
+		// extension mode dials our own endpoint, so the token never goes
+		// to a user-provided one.
+		options = append(options, playwright.BrowserTypeConnectOverCDPOptions{
+			Headers: map[string]string{"Authorization": "Bearer " + f.config.Server.AuthToken},
+		})
+	}
+	browser, err := f.pw.Chromium.ConnectOverCDP(f.config.Browser.CDPEndpoint, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to CDP endpoint %s: %w", f.config.Browser.CDPEndpoint, err)
+	}
+	f.browser = browser
+	return browser, nil
+}
+
+// CreateContext reuses the remote browser's default context when one
+// exists; a remote browser without contexts gets a fresh one.
+func (f *CDPBrowserContextFactory) CreateContext() (playwright.BrowserContext, error) {
+	browser, err := f.ensureBrowser()
+	if err != nil {
+		return nil, err
+	}
+	var context playwright.BrowserContext
+	if contexts := browser.Contexts(); len(contexts) > 0 {
+		context = contexts[0]
+	} else {
+		context, err = browser.NewContext(newContextOptions(f.config))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create context: %w", err)
+		}
+	}
+	if err := setupContext(context, f.config); err != nil {
+		return nil, err
+	}
+	return context, nil
+}
+
+// Close disconnects from the remote browser without closing it: the browser
+// belongs to whoever started it.
+func (f *CDPBrowserContextFactory) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var err error
+	if f.browser != nil {
+		err = f.browser.Close()
+		f.browser = nil
+	}
+	if f.pw != nil {
+		if stopErr := f.pw.Stop(); err == nil {
+			err = stopErr
+		}
+		f.pw = nil
+	}
+	return err
+}
@@ -0,0 +1,112 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package browser
+
+import (
+	"fmt"
+)
+
+// inspectScript collects everything the inspect tool reports about one
+// element: identity, attributes, value, text, the computed styles that
+// matter for "is this actually visible and styled right" questions, and
+// geometry.
+const inspectScript = `(args) => {
+  const registry = window.__mcpRefs;
+  if (!registry)
+    throw new Error('no snapshot captured yet; call browser_snapshot first');
+  const element = registry.byRef.get(args.ref);
+  if (!element || !element.isConnected)
+    throw new Error('stale or unknown ref "' + args.ref + '"; capture a fresh snapshot');
+  const attributes = {};
+  for (const attr of element.attributes)
+    attributes[attr.name] = attr.value;
+  const style = getComputedStyle(element);
+  const styles = {};
+  for (const name of ['display', 'visibility', 'opacity', 'color', 'background-color', 'font-size', 'font-weight'])
+    styles[name] = style.getPropertyValue(name);
+  const box = element.getBoundingClientRect();
+  const result = {
+    tag: element.tagName.toLowerCase(),
+    attributes,
+    text: (element.innerText || '').slice(0, 2000),
+    styles,
+    box: { x: Math.round(box.x), y: Math.round(box.y), width: Math.round(box.width), height: Math.round(box.height) },
+  };
+  if ('value' in element && typeof element.value === 'string')
+    result.value = element.value;
+  if ('checked' in element && typeof element.checked === 'boolean')
+    result.checked = element.checked;
+  return result;
+}`
+
+// ElementInfo is what Inspect reports about one element.
+type ElementInfo struct {
+	Tag        string
+	Attributes map[string]string
+	// Value and Checked are only meaningful for form controls; HasValue and
+	// HasChecked say whether the element carries them at all.
+	Value      string
+	HasValue   bool
+	Checked    bool
+	HasChecked bool
+	Text       string
+	Styles     map[string]string
+	Box        struct{ X, Y, Width, Height int }
+}
+
+// Inspect returns the current state of the element behind ref, resolving
+// frame-qualified refs like the other ref-based operations.
+func (t *Tab) Inspect(ref string) (*ElementInfo, error) {
+	if err := t.ResolveRef(ref); err != nil {
+		return nil, err
+	}
+	frame, local, err := t.frameForRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	value, err := frame.Evaluate(inspectScript, map[string]any{"ref": local})
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect element: %w", err)
+	}
+	raw, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected inspect result %T", value)
+	}
+	info := &ElementInfo{
+		Tag:        stringOf(raw["tag"]),
+		Text:       stringOf(raw["text"]),
+		Attributes: stringMapOf(raw["attributes"]),
+		Styles:     stringMapOf(raw["styles"]),
+	}
+	if v, ok := raw["value"].(string); ok {
+		info.Value, info.HasValue = v, true
+	}
+	if v, ok := raw["checked"].(bool); ok {
+		info.Checked, info.HasChecked = v, true
+	}
+	if box, ok := raw["box"].(map[string]any); ok {
+		info.Box.X = asInt(box["x"])
+		info.Box.Y = asInt(box["y"])
+		info.Box.Width = asInt(box["width"])
+		info.Box.Height = asInt(box["height"])
+	}
+	return info, nil
+}
+
+func stringOf(value any) string {
+	text, _ := value.(string)
+	return text
+}
+
+func stringMapOf(value any) map[string]string {
+	raw, ok := value.(map[string]any)
+	if !ok {
+		return nil
+	}
+	result := make(map[string]string, len(raw))
+	for key, entry := range raw {
+		result[key] = stringOf(entry)
+	}
+	return result
+}
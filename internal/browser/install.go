@@ -0,0 +1,64 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package browser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+
+	"github.com/microsoft/playwright-mcp/internal/config"
+)
+
+// Install downloads the configured browser (and the driver, if needed) into
+// Playwright's registry. It blocks until the download completes.
+func Install(cfg *config.FullConfig) error {
+	if err := playwright.Install(&playwright.RunOptions{Browsers: []string{InstallTarget(cfg)}}); err != nil {
+		return fmt.Errorf("failed to install %s: %w", InstallTarget(cfg), err)
+	}
+	return nil
+}
+
+// InstallTarget is the registry name passed to the installer: the channel
+// when one is configured (chrome, msedge, ...), otherwise the engine name.
+func InstallTarget(cfg *config.FullConfig) string {
+	if cfg.Browser.Channel != "" {
+		return cfg.Browser.Channel
+	}
+	if cfg.Browser.BrowserName != "" {
+		return cfg.Browser.BrowserName
+	}
+	return "chromium"
+}
+
+// isMissingExecutable reports whether a launch failed because the browser
+// was never downloaded, as opposed to crashing or being misconfigured.
+func isMissingExecutable(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "executable doesn't exist")
+}
+
+// launchError turns a missing-executable failure into an actionable message
+// and reports whether a retry is worth it. Recovery is attempted in order:
+// a system install of the configured channel (recorded as the executable
+// path for the retry), then auto-install when enabled. Other launch errors
+// pass through unchanged.
+func launchError(cfg *config.FullConfig, err error) (retry bool, wrapped error) {
+	if !isMissingExecutable(err) {
+		return false, err
+	}
+	if cfg.Browser.Channel != "" && cfg.Browser.ExecutablePath == "" {
+		if path, ok := resolveChannelExecutable(cfg.Browser.Channel); ok {
+			cfg.Browser.ExecutablePath = path
+			return true, nil
+		}
+	}
+	if cfg.Browser.AutoInstall {
+		if installErr := Install(cfg); installErr != nil {
+			return false, installErr
+		}
+		return true, nil
+	}
+	return false, fmt.Errorf("%s is not installed; run the browser_install tool or pass --auto-install (%w)", InstallTarget(cfg), err)
+}
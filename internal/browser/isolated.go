@@ -0,0 +1,212 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package browser
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/playwright-community/playwright-go"
+
+	"github.com/microsoft/playwright-mcp/internal/config"
+)
+
+// IsolatedContextFactory launches one shared browser and backs each session
+// with an ephemeral context, optionally seeded from a storage-state file so
+// logins captured once can be reused without a persistent profile.
+type IsolatedContextFactory struct {
+	config *config.FullConfig
+
+	mu       sync.Mutex
+	pw       *playwright.Playwright
+	browser  playwright.Browser
+	contexts []playwright.BrowserContext
+}
+
+func (f *IsolatedContextFactory) ensureBrowser() (playwright.Browser, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.browser != nil {
+		return f.browser, nil
+	}
+	pw, err := playwright.Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start playwright: %w", err)
+	}
+	browserType, err := browserTypeFor(pw, f.config.Browser.BrowserName)
+	if err != nil {
+		pw.Stop()
+		return nil, err
+	}
+	browser, err := browserType.Launch(launchOptions(f.config))
+	if err != nil {
+		retry, err := launchError(f.config, err)
+		if retry {
+			browser, err = browserType.Launch(launchOptions(f.config))
+		}
+		if err != nil {
+			pw.Stop()
+			return nil, fmt.Errorf("failed to launch %s: %w", f.config.Browser.BrowserName, err)
+		}
+	}
+	f.pw = pw
+	f.browser = browser
+	return browser, nil
+}
+
+// CreateContext opens an ephemeral context on the shared browser, seeded
+// from browser.storageState when configured.
+func (f *IsolatedContextFactory) CreateContext() (playwright.BrowserContext, error) {
+	browser, err := f.ensureBrowser()
+	if err != nil {
+		return nil, err
+	}
+	options := newContextOptions(f.config)
+	if state := f.config.Browser.StorageState; state != "" {
+		options.StorageStatePath = playwright.String(state)
+	}
+	context, err := browser.NewContext(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create context: %w", err)
+	}
+	if err := setupContext(context, f.config); err != nil {
+		context.Close()
+		return nil, err
+	}
+	f.mu.Lock()
+	f.contexts = append(f.contexts, context)
+	f.mu.Unlock()
+	return context, nil
+}
+
+// Close writes final storage state back to disk when requested, then tears
+// the shared browser down.
+func (f *IsolatedContextFactory) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.config.Browser.SaveStorageState {
+		path := f.config.Browser.StorageState
+		if path == "" {
+			path = filepath.Join(f.config.OutputDir, "storage-state.json")
+		}
+		for _, context := range f.contexts {
+			context.StorageState(path)
+		}
+	}
+	f.contexts = nil
+	var err error
+	if f.browser != nil {
+		err = f.browser.Close()
+		f.browser = nil
+	}
+	if f.pw != nil {
+		if stopErr := f.pw.Stop(); err == nil {
+			err = stopErr
+		}
+		f.pw = nil
+	}
+	return err
+}
+
+// launchOptions maps the config onto browser-level launch options for the
+// shared-browser modes.
+func launchOptions(cfg *config.FullConfig) playwright.BrowserTypeLaunchOptions {
+	options := playwright.BrowserTypeLaunchOptions{
+		Headless: playwright.Bool(cfg.Browser.Headless),
+	}
+	if cfg.Browser.Channel != "" {
+		options.Channel = playwright.String(cfg.Browser.Channel)
+	}
+	if cfg.Browser.ExecutablePath != "" {
+		options.ExecutablePath = playwright.String(cfg.Browser.ExecutablePath)
+	}
+	if cfg.Browser.ProxyServer != "" {
+		options.Proxy = &playwright.Proxy{Server: cfg.Browser.ProxyServer}
+	}
+	return options
+}
+
+// newContextOptions maps the config onto per-context options, mirroring the
+// persistent launch options for the ephemeral-context modes.
+func newContextOptions(cfg *config.FullConfig) playwright.BrowserNewContextOptions {
+	contextOptions := cfg.Browser.ContextOptions
+	options := playwright.BrowserNewContextOptions{
+		AcceptDownloads: playwright.Bool(true),
+	}
+	if viewport := contextOptions.Viewport; viewport != nil {
+		options.Viewport = &playwright.Size{Width: viewport.Width, Height: viewport.Height}
+	}
+	if contextOptions.UserAgent != "" {
+		options.UserAgent = playwright.String(contextOptions.UserAgent)
+	}
+	if len(contextOptions.ExtraHTTPHeaders) > 0 {
+		options.ExtraHttpHeaders = contextOptions.ExtraHTTPHeaders
+	}
+	if contextOptions.Locale != "" {
+		options.Locale = playwright.String(contextOptions.Locale)
+	}
+	if contextOptions.TimezoneID != "" {
+		options.TimezoneId = playwright.String(contextOptions.TimezoneID)
+	}
+	switch contextOptions.ColorScheme {
+	case "light":
+		options.ColorScheme = playwright.ColorSchemeLight
+	case "dark":
+		options.ColorScheme = playwright.ColorSchemeDark
+	case "no-preference":
+		options.ColorScheme = playwright.ColorSchemeNoPreference
+	}
+	if geolocation := contextOptions.Geolocation; geolocation != nil {
+		options.Geolocation = &playwright.Geolocation{
+			Latitude:  geolocation.Latitude,
+			Longitude: geolocation.Longitude,
+		}
+	}
+	if contextOptions.DeviceScaleFactor != 0 {
+		options.DeviceScaleFactor = playwright.Float(contextOptions.DeviceScaleFactor)
+	}
+	if contextOptions.IsMobile {
+		options.IsMobile = playwright.Bool(true)
+	}
+	if contextOptions.HasTouch {
+		options.HasTouch = playwright.Bool(true)
+	}
+	if len(contextOptions.Permissions) > 0 {
+		options.Permissions = contextOptions.Permissions
+	}
+	if credentials := contextOptions.HTTPCredentials; credentials != nil {
+		options.HttpCredentials = &playwright.HttpCredentials{
+			Username: credentials.Username,
+			Password: credentials.Password,
+		}
+	}
+	if certificates := clientCertificateOptions(cfg); certificates != nil {
+		options.ClientCertificates = certificates
+	}
+	if proxy := contextOptions.Proxy; proxy != nil {
+		option := &playwright.Proxy{Server: proxy.Server}
+		if proxy.Username != "" {
+			option.Username = playwright.String(proxy.Username)
+		}
+		if proxy.Password != "" {
+			option.Password = playwright.String(proxy.Password)
+		}
+		if proxy.Bypass != "" {
+			option.Bypass = playwright.String(proxy.Bypass)
+		}
+		options.Proxy = option
+	}
+	if size := cfg.SaveVideo; size != nil {
+		options.RecordVideo = &playwright.RecordVideo{
+			Dir:  cfg.VideosDir(),
+			Size: &playwright.Size{Width: size.Width, Height: size.Height},
+		}
+	}
+	if cfg.SaveHar {
+		options.RecordHarPath = playwright.String(cfg.HarPath())
+		options.RecordHarContent = harContentPolicy(cfg)
+	}
+	return options
+}
@@ -0,0 +1,267 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package browser
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/microsoft/playwright-mcp/internal/config"
+)
+
+// BrowserInfo describes one managed browser instance.
+type BrowserInfo struct {
+	Name    string `json:"name"`
+	CDPPort int    `json:"cdpPort"`
+	PID     int    `json:"pid"`
+	// Error records why the instance is no longer usable, e.g. an
+	// unexpected process exit.
+	Error string `json:"error,omitempty"`
+}
+
+// BrowserManager launches and tracks named browser instances, each with its
+// own profile and CDP port, so MCP sessions can attach to a specific
+// instance and instances can be inspected and closed individually.
+type BrowserManager struct {
+	config *config.FullConfig
+
+	mu        sync.Mutex
+	instances map[string]*browserInstance
+}
+
+type browserInstance struct {
+	info       BrowserInfo
+	profileDir string
+	cmd        *exec.Cmd
+	// closing marks a shutdown we initiated, so the supervisor does not
+	// report it as an unexpected exit.
+	closing bool
+	// done is closed once the process has been reaped.
+	done chan struct{}
+}
+
+// NewBrowserManager creates a manager; no browser starts until Launch.
+func NewBrowserManager(cfg *config.FullConfig) *BrowserManager {
+	return &BrowserManager{
+		config:    cfg,
+		instances: make(map[string]*browserInstance),
+	}
+}
+
+// Launch starts a named browser instance with CDP enabled on a free port
+// and its own temporary profile. Names must be unique among live instances.
+func (m *BrowserManager) Launch(name string) (BrowserInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.instances[name]; exists {
+		return BrowserInfo{}, fmt.Errorf("browser instance %q already exists", name)
+	}
+	executable, err := m.executable()
+	if err != nil {
+		return BrowserInfo{}, err
+	}
+	port, err := freePort()
+	if err != nil {
+		return BrowserInfo{}, err
+	}
+	profileDir, err := os.MkdirTemp("", "playwright-mcp-"+name+"-")
+	if err != nil {
+		return BrowserInfo{}, fmt.Errorf("failed to create profile for %q: %w", name, err)
+	}
+	args := []string{
+		fmt.Sprintf("--remote-debugging-port=%d", port),
+		"--user-data-dir=" + profileDir,
+		"--no-first-run",
+		"--no-default-browser-check",
+	}
+	if m.config.Browser.Headless {
+		args = append(args, "--headless=new")
+	}
+	cmd := exec.Command(executable, args...)
+	setPdeathsig(cmd)
+	if err := cmd.Start(); err != nil {
+		os.RemoveAll(profileDir)
+		return BrowserInfo{}, fmt.Errorf("failed to start %s: %w", executable, err)
+	}
+	instance := &browserInstance{
+		info:       BrowserInfo{Name: name, CDPPort: port, PID: cmd.Process.Pid},
+		profileDir: profileDir,
+		cmd:        cmd,
+		done:       make(chan struct{}),
+	}
+	m.instances[name] = instance
+	go m.supervise(name, instance)
+	return instance.info, nil
+}
+
+// supervise reaps the process and records why it went away. Shutdowns we
+// initiated stay silent; anything else lands in BrowserInfo.Error so List
+// explains a dead instance instead of showing a stale port.
+func (m *BrowserManager) supervise(name string, instance *browserInstance) {
+	err := instance.cmd.Wait()
+	close(instance.done)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	current, ok := m.instances[name]
+	if !ok || current != instance || instance.closing {
+		return
+	}
+	reason := "exit status 0"
+	if err != nil {
+		reason = err.Error()
+	}
+	instance.info.Error = fmt.Sprintf("browser process exited unexpectedly: %s", reason)
+	// The process is gone, so its temporary profile is garbage; the entry
+	// itself stays so List can explain what happened until Close removes it.
+	os.RemoveAll(instance.profileDir)
+}
+
+// executable resolves the browser binary for managed instances: an explicit
+// path wins, then a system install of the configured channel. Managed
+// instances are driven over raw CDP and therefore require chromium.
+func (m *BrowserManager) executable() (string, error) {
+	if path := m.config.Browser.ExecutablePath; path != "" {
+		return path, nil
+	}
+	channel := m.config.Browser.Channel
+	if channel == "" {
+		channel = "chrome"
+	}
+	if path, ok := resolveChannelExecutable(channel); ok {
+		return path, nil
+	}
+	return "", fmt.Errorf("no browser executable found for channel %q; set browser.executablePath", channel)
+}
+
+// List returns the known instances sorted by name.
+func (m *BrowserManager) List() []BrowserInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	infos := make([]BrowserInfo, 0, len(m.instances))
+	for _, instance := range m.instances {
+		infos = append(infos, instance.info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// Factory returns a context factory attached to the named instance, so an
+// MCP session can be bound to one specific managed browser.
+func (m *BrowserManager) Factory(name string) (ContextFactory, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	instance, ok := m.instances[name]
+	if !ok {
+		return nil, fmt.Errorf("no browser instance named %q", name)
+	}
+	if instance.info.Error != "" {
+		return nil, fmt.Errorf("browser instance %q is dead: %s", name, instance.info.Error)
+	}
+	attached := *m.config
+	attached.Browser.CDPEndpoint = fmt.Sprintf("http://127.0.0.1:%d", instance.info.CDPPort)
+	return &CDPBrowserContextFactory{config: &attached}, nil
+}
+
+// Close shuts the named instance down gracefully by closing each of its
+// CDP targets via /json/close, escalates to SIGKILL if the browser does
+// not exit on its own, and removes its temporary profile.
+func (m *BrowserManager) Close(name string) error {
+	m.mu.Lock()
+	instance, ok := m.instances[name]
+	if ok {
+		instance.closing = true
+	}
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no browser instance named %q", name)
+	}
+	var err error
+	if instance.info.Error == "" {
+		err = closeCDPTargets(instance.info.CDPPort)
+	}
+	select {
+	case <-instance.done:
+	case <-time.After(5 * time.Second):
+		instance.cmd.Process.Kill()
+		<-instance.done
+	}
+	m.mu.Lock()
+	delete(m.instances, name)
+	m.mu.Unlock()
+	os.RemoveAll(instance.profileDir)
+	return err
+}
+
+// CloseAll closes every instance, keeping the first error.
+func (m *BrowserManager) CloseAll() error {
+	var firstErr error
+	for _, info := range m.List() {
+		if err := m.Close(info.Name); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// KillAll force-kills every tracked process without waiting for a graceful
+// shutdown. It is meant for defer and panic paths: whatever happens to the
+// server, no orphaned browsers are left behind.
+func (m *BrowserManager) KillAll() {
+	m.mu.Lock()
+	instances := make([]*browserInstance, 0, len(m.instances))
+	for _, instance := range m.instances {
+		instance.closing = true
+		instances = append(instances, instance)
+	}
+	m.instances = make(map[string]*browserInstance)
+	m.mu.Unlock()
+	for _, instance := range instances {
+		instance.cmd.Process.Kill()
+		<-instance.done
+		os.RemoveAll(instance.profileDir)
+	}
+}
+
+// closeCDPTargets lists the browser's targets over the CDP HTTP endpoint
+// and closes each one.
+func closeCDPTargets(port int) error {
+	base := fmt.Sprintf("http://127.0.0.1:%d", port)
+	response, err := http.Get(base + "/json/list")
+	if err != nil {
+		return fmt.Errorf("failed to list CDP targets: %w", err)
+	}
+	defer response.Body.Close()
+	var targets []struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&targets); err != nil {
+		return fmt.Errorf("failed to decode CDP target list: %w", err)
+	}
+	for _, target := range targets {
+		closeResponse, err := http.Get(base + "/json/close/" + target.ID)
+		if err != nil {
+			return fmt.Errorf("failed to close CDP target %s: %w", target.ID, err)
+		}
+		closeResponse.Body.Close()
+	}
+	return nil
+}
+
+// freePort asks the kernel for an unused TCP port on the loopback
+// interface.
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("failed to allocate a CDP port: %w", err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package browser
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+
+	"github.com/microsoft/playwright-mcp/internal/config"
+)
+
+// installNetworkRules enforces network.allowedOrigins/blockedOrigins with a
+// context-level route. Blocked subresource requests are aborted; blocked
+// navigations are fulfilled with a synthetic error page so the agent sees
+// why the page did not load instead of a bare net:: error.
+func installNetworkRules(context playwright.BrowserContext, cfg *config.FullConfig) error {
+	allowed := cfg.Network.AllowedOrigins
+	blocked := cfg.Network.BlockedOrigins
+	if len(allowed) == 0 && len(blocked) == 0 {
+		return nil
+	}
+	err := context.Route("**/*", func(route playwright.Route) {
+		request := route.Request()
+		if originAllowed(request.URL(), allowed, blocked) {
+			route.Continue()
+			return
+		}
+		if request.IsNavigationRequest() {
+			route.Fulfill(playwright.RouteFulfillOptions{
+				Status:      playwright.Int(403),
+				ContentType: playwright.String("text/plain"),
+				Body:        fmt.Sprintf("Navigation to %s was blocked by the server's network configuration (network.allowedOrigins / network.blockedOrigins).", request.URL()),
+			})
+			return
+		}
+		route.Abort("blockedbyclient")
+	})
+	if err != nil {
+		return fmt.Errorf("failed to install network rules: %w", err)
+	}
+	return nil
+}
+
+// originAllowed applies the rules to one request URL: the block list wins,
+// and a non-empty allow list blocks everything it does not cover.
+func originAllowed(rawURL string, allowed, blocked []string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range blocked {
+		if matchOrigin(pattern, parsed) {
+			return false
+		}
+	}
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, pattern := range allowed {
+		if matchOrigin(pattern, parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchOrigin matches a pattern against both the bare host and the full
+// origin, so "example.com", "*.example.com" and "https://example.com" all
+// do what they look like. "*" matches any run of characters.
+func matchOrigin(pattern string, parsed *url.URL) bool {
+	pattern = strings.ToLower(pattern)
+	host := strings.ToLower(parsed.Host)
+	origin := strings.ToLower(parsed.Scheme) + "://" + host
+	return wildcardMatch(pattern, host) || wildcardMatch(pattern, origin)
+}
+
+func wildcardMatch(pattern, value string) bool {
+	expression := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	matched, err := regexp.MatchString(expression, value)
+	return err == nil && matched
+}
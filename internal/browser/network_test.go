@@ -0,0 +1,51 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package browser
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestWildcardMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"example.com", "example.com", true},
+		{"example.com", "foo.example.com", false},
+		{"*.example.com", "foo.example.com", true},
+		{"*.example.com", "example.com", false},
+		{"*", "anything.at.all", true},
+		{"example.*", "example.com", true},
+	}
+	for _, tt := range tests {
+		if got := wildcardMatch(tt.pattern, tt.value); got != tt.want {
+			t.Errorf("wildcardMatch(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMatchOrigin(t *testing.T) {
+	parsed, err := url.Parse("https://Foo.Example.com/path?q=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		pattern string
+		want    bool
+	}{
+		{"foo.example.com", true},
+		{"https://foo.example.com", true},
+		{"*.example.com", true},
+		{"http://foo.example.com", false},
+		{"bar.example.com", false},
+	}
+	for _, tt := range tests {
+		if got := matchOrigin(tt.pattern, parsed); got != tt.want {
+			t.Errorf("matchOrigin(%q, %s) = %v, want %v", tt.pattern, parsed, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package browser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/playwright-community/playwright-go"
+
+	"github.com/microsoft/playwright-mcp/internal/config"
+)
+
+// PlaywrightContextFactory launches a browser locally and backs each session
+// with a persistent context rooted at the configured user data directory —
+// the default mode, matching the TypeScript server.
+type PlaywrightContextFactory struct {
+	config *config.FullConfig
+
+	mu sync.Mutex
+	pw *playwright.Playwright
+}
+
+// ensurePlaywright starts the Playwright driver on first use.
+func (f *PlaywrightContextFactory) ensurePlaywright() (*playwright.Playwright, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.pw != nil {
+		return f.pw, nil
+	}
+	pw, err := playwright.Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start playwright: %w", err)
+	}
+	f.pw = pw
+	return pw, nil
+}
+
+// CreateContext launches a persistent context honoring the user data
+// directory, launch options and context options from the config.
+func (f *PlaywrightContextFactory) CreateContext() (playwright.BrowserContext, error) {
+	pw, err := f.ensurePlaywright()
+	if err != nil {
+		return nil, err
+	}
+	browserType, err := browserTypeFor(pw, f.config.Browser.BrowserName)
+	if err != nil {
+		return nil, err
+	}
+	userDataDir := f.config.Browser.UserDataDir
+	if userDataDir == "" {
+		userDataDir, err = defaultUserDataDir(f.config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if pid, held := profileLockPID(userDataDir); held {
+		if !f.config.Browser.ForceProfile {
+			return nil, fmt.Errorf("user data directory %s is already in use by process %d; close that browser, use --isolated, or pass --force-profile to work on a temporary copy", userDataDir, pid)
+		}
+		userDataDir, err = cloneProfile(userDataDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	context, err := browserType.LaunchPersistentContext(userDataDir, launchPersistentOptions(f.config))
+	if err != nil {
+		retry, err := launchError(f.config, err)
+		if !retry {
+			return nil, fmt.Errorf("failed to launch %s: %w", f.config.Browser.BrowserName, err)
+		}
+		context, err = browserType.LaunchPersistentContext(userDataDir, launchPersistentOptions(f.config))
+		if err != nil {
+			return nil, fmt.Errorf("failed to launch %s: %w", f.config.Browser.BrowserName, err)
+		}
+	}
+	if err := setupContext(context, f.config); err != nil {
+		context.Close()
+		return nil, err
+	}
+	return context, nil
+}
+
+func (f *PlaywrightContextFactory) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.pw == nil {
+		return nil
+	}
+	err := f.pw.Stop()
+	f.pw = nil
+	return err
+}
+
+// defaultUserDataDir is the profile used when neither -user-data-dir nor
+// -isolated is given: a stable per-channel directory under the OS cache
+// dir, matching the TypeScript server's ms-playwright/mcp-<channel>-profile
+// so cookies and logins survive between runs.
+func defaultUserDataDir(cfg *config.FullConfig) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the user cache directory: %w", err)
+	}
+	channel := cfg.Browser.Channel
+	if channel == "" {
+		channel = cfg.Browser.BrowserName
+	}
+	if channel == "" {
+		channel = "chromium"
+	}
+	dir := filepath.Join(cacheDir, "ms-playwright", fmt.Sprintf("mcp-%s-profile", channel))
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	return dir, nil
+}
+
+// launchPersistentOptions maps the resolved config onto Playwright's
+// persistent-context launch options.
+func launchPersistentOptions(cfg *config.FullConfig) playwright.BrowserTypeLaunchPersistentContextOptions {
+	browser := cfg.Browser
+	contextOptions := browser.ContextOptions
+	options := playwright.BrowserTypeLaunchPersistentContextOptions{
+		Headless:        playwright.Bool(browser.Headless),
+		AcceptDownloads: playwright.Bool(true),
+		DownloadsPath:   playwright.String(cfg.DownloadsDir()),
+	}
+	if browser.Channel != "" {
+		options.Channel = playwright.String(browser.Channel)
+	}
+	if browser.ExecutablePath != "" {
+		options.ExecutablePath = playwright.String(browser.ExecutablePath)
+	}
+	if proxy := proxyOption(cfg); proxy != nil {
+		options.Proxy = proxy
+	}
+	if viewport := contextOptions.Viewport; viewport != nil {
+		options.Viewport = &playwright.Size{Width: viewport.Width, Height: viewport.Height}
+	}
+	if contextOptions.UserAgent != "" {
+		options.UserAgent = playwright.String(contextOptions.UserAgent)
+	}
+	if len(contextOptions.ExtraHTTPHeaders) > 0 {
+		options.ExtraHttpHeaders = contextOptions.ExtraHTTPHeaders
+	}
+	if contextOptions.Locale != "" {
+		options.Locale = playwright.String(contextOptions.Locale)
+	}
+	if contextOptions.TimezoneID != "" {
+		options.TimezoneId = playwright.String(contextOptions.TimezoneID)
+	}
+	switch contextOptions.ColorScheme {
+	case "light":
+		options.ColorScheme = playwright.ColorSchemeLight
+	case "dark":
+		options.ColorScheme = playwright.ColorSchemeDark
+	case "no-preference":
+		options.ColorScheme = playwright.ColorSchemeNoPreference
+	}
+	if geolocation := contextOptions.Geolocation; geolocation != nil {
+		options.Geolocation = &playwright.Geolocation{
+			Latitude:  geolocation.Latitude,
+			Longitude: geolocation.Longitude,
+		}
+	}
+	if contextOptions.DeviceScaleFactor != 0 {
+		options.DeviceScaleFactor = playwright.Float(contextOptions.DeviceScaleFactor)
+	}
+	if contextOptions.IsMobile {
+		options.IsMobile = playwright.Bool(true)
+	}
+	if contextOptions.HasTouch {
+		options.HasTouch = playwright.Bool(true)
+	}
+	if len(contextOptions.Permissions) > 0 {
+		options.Permissions = contextOptions.Permissions
+	}
+	if credentials := contextOptions.HTTPCredentials; credentials != nil {
+		options.HttpCredentials = &playwright.HttpCredentials{
+			Username: credentials.Username,
+			Password: credentials.Password,
+		}
+	}
+	if certificates := clientCertificateOptions(cfg); certificates != nil {
+		options.ClientCertificates = certificates
+	}
+	if size := cfg.SaveVideo; size != nil {
+		options.RecordVideo = &playwright.RecordVideo{
+			Dir:  cfg.VideosDir(),
+			Size: &playwright.Size{Width: size.Width, Height: size.Height},
+		}
+	}
+	if cfg.SaveHar {
+		options.RecordHarPath = playwright.String(cfg.HarPath())
+		options.RecordHarContent = harContentPolicy(cfg)
+	}
+	return options
+}
+
+// harContentPolicy maps saveHarContent onto Playwright's policy enum;
+// embed is the default, matching Playwright.
+func harContentPolicy(cfg *config.FullConfig) *playwright.HarContentPolicy {
+	switch cfg.SaveHarContent {
+	case "attach":
+		return playwright.HarContentPolicyAttach
+	case "omit":
+		return playwright.HarContentPolicyOmit
+	}
+	return playwright.HarContentPolicyEmbed
+}
+
+// clientCertificateOptions maps the configured mTLS client certificates
+// onto Playwright's shape; nil when none are configured.
+func clientCertificateOptions(cfg *config.FullConfig) []playwright.ClientCertificate {
+	var certificates []playwright.ClientCertificate
+	for _, certificate := range cfg.Browser.ContextOptions.ClientCertificates {
+		option := playwright.ClientCertificate{Origin: certificate.Origin}
+		if certificate.CertPath != "" {
+			option.CertPath = playwright.String(certificate.CertPath)
+		}
+		if certificate.KeyPath != "" {
+			option.KeyPath = playwright.String(certificate.KeyPath)
+		}
+		if certificate.PfxPath != "" {
+			option.PfxPath = playwright.String(certificate.PfxPath)
+		}
+		if certificate.Passphrase != "" {
+			option.Passphrase = playwright.String(certificate.Passphrase)
+		}
+		certificates = append(certificates, option)
+	}
+	return certificates
+}
+
+// proxyOption prefers the per-context proxy and falls back to the
+// launch-level --proxy-server.
+func proxyOption(cfg *config.FullConfig) *playwright.Proxy {
+	if proxy := cfg.Browser.ContextOptions.Proxy; proxy != nil {
+		option := &playwright.Proxy{Server: proxy.Server}
+		if proxy.Username != "" {
+			option.Username = playwright.String(proxy.Username)
+		}
+		if proxy.Password != "" {
+			option.Password = playwright.String(proxy.Password)
+		}
+		if proxy.Bypass != "" {
+			option.Bypass = playwright.String(proxy.Bypass)
+		}
+		return option
+	}
+	if cfg.Browser.ProxyServer != "" {
+		return &playwright.Proxy{Server: cfg.Browser.ProxyServer}
+	}
+	return nil
+}
+
+// setupContext applies the settings that cannot be expressed as launch
+// options: default timeouts, init scripts and network origin rules.
+func setupContext(context playwright.BrowserContext, cfg *config.FullConfig) error {
+	if err := installNetworkRules(context, cfg); err != nil {
+		return err
+	}
+	if cfg.ReplayHar != "" {
+		options := playwright.BrowserContextRouteFromHAROptions{}
+		if cfg.ReplayHarUpdate {
+			options.Update = playwright.Bool(true)
+		}
+		if err := context.RouteFromHAR(cfg.ReplayHar, options); err != nil {
+			return fmt.Errorf("failed to route from HAR %s: %w", cfg.ReplayHar, err)
+		}
+	}
+	if timeout := cfg.Timeouts.Action; timeout != 0 {
+		context.SetDefaultTimeout(float64(timeout.Std().Milliseconds()))
+	}
+	if timeout := cfg.Timeouts.Navigation; timeout != 0 {
+		context.SetDefaultNavigationTimeout(float64(timeout.Std().Milliseconds()))
+	}
+	for _, script := range cfg.Browser.InitScripts {
+		data, err := os.ReadFile(script)
+		if err != nil {
+			return fmt.Errorf("failed to read init script %s: %w", script, err)
+		}
+		if err := context.AddInitScript(playwright.Script{Content: playwright.String(string(data))}); err != nil {
+			return fmt.Errorf("failed to add init script %s: %w", script, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package browser
+
+import (
+	"sync"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// PooledContextFactory wraps another factory and keeps a number of
+// pre-launched contexts warm, refilled in the background, so a new isolated
+// session gets a context in milliseconds instead of paying browser and
+// context startup on the critical path.
+type PooledContextFactory struct {
+	inner ContextFactory
+	pool  chan playwright.BrowserContext
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewPooledContextFactory pools up to size contexts from inner and starts
+// filling immediately.
+func NewPooledContextFactory(inner ContextFactory, size int) *PooledContextFactory {
+	f := &PooledContextFactory{
+		inner: inner,
+		pool:  make(chan playwright.BrowserContext, size),
+	}
+	go f.refill()
+	return f
+}
+
+// refill tops the pool up to capacity. Creation errors stop the pass;
+// callers hitting an empty pool fall through to direct creation and see the
+// real error there.
+func (f *PooledContextFactory) refill() {
+	for {
+		f.mu.Lock()
+		closed := f.closed
+		f.mu.Unlock()
+		if closed || len(f.pool) == cap(f.pool) {
+			return
+		}
+		context, err := f.inner.CreateContext()
+		if err != nil {
+			return
+		}
+		select {
+		case f.pool <- context:
+		default:
+			// Filled up (or closed) while we were creating.
+			context.Close()
+			return
+		}
+	}
+}
+
+// CreateContext hands out a warm context when one is ready and falls back
+// to direct creation otherwise; either way a background refill is kicked
+// off to replace what was taken.
+func (f *PooledContextFactory) CreateContext() (playwright.BrowserContext, error) {
+	select {
+	case context := <-f.pool:
+		go f.refill()
+		return context, nil
+	default:
+	}
+	context, err := f.inner.CreateContext()
+	if err != nil {
+		return nil, err
+	}
+	go f.refill()
+	return context, nil
+}
+
+// Close drains and closes the warm contexts, then the inner factory.
+func (f *PooledContextFactory) Close() error {
+	f.mu.Lock()
+	f.closed = true
+	f.mu.Unlock()
+	for {
+		select {
+		case context := <-f.pool:
+			context.Close()
+		default:
+			return f.inner.Close()
+		}
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+//go:build windows
+
+package browser
+
+import "os"
+
+// processAlive reports whether a process with the given PID exists. On
+// Windows os.FindProcess opens a real handle and fails when there is no
+// such process.
+func processAlive(pid int) bool {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	process.Release()
+	return true
+}
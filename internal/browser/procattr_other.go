@@ -0,0 +1,14 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+//go:build !linux
+
+package browser
+
+import "os/exec"
+
+// setPdeathsig is a no-op where the kernel offers no parent-death signal.
+// The deferred KillAll in main covers panics on the main goroutine; a crash
+// on any other goroutine can still orphan managed browsers here, which only
+// Linux's pdeathsig prevents.
+func setPdeathsig(cmd *exec.Cmd) {}
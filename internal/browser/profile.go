@@ -0,0 +1,90 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package browser
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// profileLockPID reports the PID of a live browser holding the profile at
+// dir, by reading Chromium's SingletonLock symlink (its target encodes
+// "hostname-pid"). A stale lock left by a crashed browser is not reported:
+// Chromium recovers from those on its own.
+func profileLockPID(dir string) (int, bool) {
+	target, err := os.Readlink(filepath.Join(dir, "SingletonLock"))
+	if err != nil {
+		return 0, false
+	}
+	index := strings.LastIndex(target, "-")
+	if index < 0 {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(target[index+1:])
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	if !processAlive(pid) {
+		return 0, false
+	}
+	return pid, true
+}
+
+// cloneProfile copies the profile into a fresh temp directory so a second
+// server can run against a busy profile. Singleton artifacts are skipped:
+// they belong to the process holding the original.
+func cloneProfile(dir string) (string, error) {
+	clone, err := os.MkdirTemp("", "playwright-mcp-profile-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create profile clone: %w", err)
+	}
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := entry.Name()
+		if strings.HasPrefix(name, "Singleton") || name == "lockfile" {
+			return nil
+		}
+		relative, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		destination := filepath.Join(clone, relative)
+		if entry.IsDir() {
+			return os.MkdirAll(destination, 0o700)
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		return copyFile(path, destination)
+	})
+	if err != nil {
+		os.RemoveAll(clone)
+		return "", fmt.Errorf("failed to clone profile %s: %w", dir, err)
+	}
+	return clone, nil
+}
+
+func copyFile(source, destination string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(destination)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
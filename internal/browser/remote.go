@@ -0,0 +1,91 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package browser
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/playwright-community/playwright-go"
+
+	"github.com/microsoft/playwright-mcp/internal/config"
+)
+
+// RemoteContextFactory drives a browser hosted on a remote Playwright
+// server (a grid or browser-as-a-service endpoint) via BrowserType.Connect,
+// instead of launching locally. Sessions get ephemeral contexts on the
+// remote browser.
+type RemoteContextFactory struct {
+	config *config.FullConfig
+
+	mu      sync.Mutex
+	pw      *playwright.Playwright
+	browser playwright.Browser
+}
+
+func (f *RemoteContextFactory) ensureBrowser() (playwright.Browser, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.browser != nil && f.browser.IsConnected() {
+		return f.browser, nil
+	}
+	if f.pw == nil {
+		pw, err := playwright.Run()
+		if err != nil {
+			return nil, fmt.Errorf("failed to start playwright: %w", err)
+		}
+		f.pw = pw
+	}
+	browserType, err := browserTypeFor(f.pw, f.config.Browser.BrowserName)
+	if err != nil {
+		return nil, err
+	}
+	options := playwright.BrowserTypeConnectOptions{}
+	// Headers pass authentication through to the remote endpoint, e.g.
+	// bearer tokens required by hosted browser providers.
+	if headers := f.config.Browser.ConnectWSHeaders; len(headers) > 0 {
+		options.Headers = headers
+	}
+	browser, err := browserType.Connect(f.config.Browser.ConnectWS, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", f.config.Browser.ConnectWS, err)
+	}
+	f.browser = browser
+	return browser, nil
+}
+
+func (f *RemoteContextFactory) CreateContext() (playwright.BrowserContext, error) {
+	browser, err := f.ensureBrowser()
+	if err != nil {
+		return nil, err
+	}
+	context, err := browser.NewContext(newContextOptions(f.config))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create context: %w", err)
+	}
+	if err := setupContext(context, f.config); err != nil {
+		context.Close()
+		return nil, err
+	}
+	return context, nil
+}
+
+// Close disconnects from the remote server; the browser's lifetime is the
+// provider's concern.
+func (f *RemoteContextFactory) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var err error
+	if f.browser != nil {
+		err = f.browser.Close()
+		f.browser = nil
+	}
+	if f.pw != nil {
+		if stopErr := f.pw.Stop(); err == nil {
+			err = stopErr
+		}
+		f.pw = nil
+	}
+	return err
+}
@@ -0,0 +1,948 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package browser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// snapshotScript walks the DOM and renders an ARIA-style outline: one line
+// per node with role, accessible name and state. Interactable elements get
+// a stable ref (e1, e2, ...) kept in window.__mcpRefs — a WeakMap from
+// element to ref, so the same element keeps its ref across snapshots and
+// tools can resolve a ref back to the element. An optional selector scopes
+// the walk to a subtree instead of the whole page, and an optional filter
+// drops nodes by role, selector or boilerplate area.
+const snapshotScript = `(args) => {
+  args = args || {};
+  const selector = args.selector;
+  const geometry = !!args.geometry;
+  const filter = args.filter || {};
+  const excludedRoles = new Set(filter.roles || []);
+  const registry = window.__mcpRefs = window.__mcpRefs || {
+    counter: 0,
+    byRef: new Map(),
+    byElement: new WeakMap(),
+    meta: new Map(),
+  };
+
+  // The dirty flag backs snapshot caching: any DOM mutation after a full
+  // capture invalidates it, and a clean flag lets the server reuse the
+  // cached snapshot. Navigations replace the document (and the registry),
+  // which reads as dirty.
+  if (!registry.observer && document.documentElement) {
+    registry.observer = new MutationObserver(() => { registry.dirty = true; });
+    registry.observer.observe(document.documentElement,
+        {subtree: true, childList: true, attributes: true, characterData: true});
+  }
+
+  function refFor(element, role, name) {
+    let ref = registry.byElement.get(element);
+    if (!ref) {
+      ref = 'e' + (++registry.counter);
+      registry.byElement.set(element, ref);
+      registry.byRef.set(ref, element);
+    }
+    registry.meta.set(ref, {role, name});
+    return ref;
+  }
+
+  const roleByTag = {
+    a: 'link', button: 'button', select: 'combobox', textarea: 'textbox',
+    img: 'img', nav: 'navigation', main: 'main', header: 'banner',
+    footer: 'contentinfo', aside: 'complementary', form: 'form',
+    table: 'table', ul: 'list', ol: 'list', li: 'listitem',
+    h1: 'heading', h2: 'heading', h3: 'heading', h4: 'heading',
+    h5: 'heading', h6: 'heading', dialog: 'dialog', option: 'option',
+    article: 'article', section: 'region',
+  };
+  const roleByInputType = {
+    button: 'button', submit: 'button', reset: 'button', image: 'button',
+    checkbox: 'checkbox', radio: 'radio', range: 'slider',
+    number: 'spinbutton', search: 'searchbox',
+  };
+
+  function roleOf(element) {
+    const explicit = element.getAttribute('role');
+    if (explicit)
+      return explicit;
+    const tag = element.tagName.toLowerCase();
+    if (tag === 'input')
+      return roleByInputType[element.type] || 'textbox';
+    if (tag === 'a' && !element.hasAttribute('href'))
+      return 'generic';
+    return roleByTag[tag] || 'generic';
+  }
+
+  function nameOf(element) {
+    const label = element.getAttribute('aria-label');
+    if (label)
+      return label;
+    const labelledBy = element.getAttribute('aria-labelledby');
+    if (labelledBy) {
+      const target = document.getElementById(labelledBy.split(/\s+/)[0]);
+      if (target)
+        return target.textContent.trim();
+    }
+    if (element.tagName === 'IMG')
+      return element.getAttribute('alt') || '';
+    if (element.tagName === 'INPUT') {
+      if (element.labels && element.labels.length)
+        return element.labels[0].textContent.trim();
+      return element.getAttribute('placeholder') || element.value || '';
+    }
+    const text = element.textContent.trim().replace(/\s+/g, ' ');
+    return text.length > 80 ? text.slice(0, 77) + '...' : text;
+  }
+
+  function isVisible(element) {
+    const style = getComputedStyle(element);
+    if (style.display === 'none' || style.visibility === 'hidden')
+      return false;
+    const rect = element.getBoundingClientRect();
+    return rect.width > 0 || rect.height > 0;
+  }
+
+  const interactableRoles = new Set([
+    'link', 'button', 'checkbox', 'radio', 'combobox', 'textbox',
+    'searchbox', 'slider', 'spinbutton', 'option', 'menuitem', 'tab',
+    'switch',
+  ]);
+
+  function isInteractable(element, role) {
+    return interactableRoles.has(role) || element.hasAttribute('onclick') ||
+        element.tabIndex >= 0;
+  }
+
+  function statesOf(element) {
+    const states = [];
+    if (element.disabled)
+      states.push('disabled');
+    if (element.checked)
+      states.push('checked');
+    if (element.selected)
+      states.push('selected');
+    const expanded = element.getAttribute('aria-expanded');
+    if (expanded === 'true')
+      states.push('expanded');
+    if (expanded === 'false')
+      states.push('collapsed');
+    if (element.getAttribute('aria-current'))
+      states.push('current');
+    return states;
+  }
+
+  // Re-resolution (resolveRefScript) and element queries (findScript)
+  // reuse the same role, name, ref and visibility computations the
+  // snapshot used.
+  registry.roleOf = roleOf;
+  registry.nameOf = nameOf;
+  registry.refFor = refFor;
+  registry.isVisible = isVisible;
+
+  function matchesArea(element, role, area) {
+    const idClass = (element.id + ' ' + (element.getAttribute('class') || '')).toLowerCase();
+    switch (area) {
+      case 'ads':
+        return /(^|[-_ ])(ad|ads|advert|sponsor)([-_ ]|$)/.test(idClass) ||
+            element.hasAttribute('data-ad');
+      case 'cookie-banners':
+        return /(cookie|consent|gdpr)/.test(idClass);
+      case 'nav':
+        return role === 'navigation';
+      case 'footer':
+        return role === 'contentinfo';
+      case 'hidden':
+        return element.getAttribute('aria-hidden') === 'true';
+    }
+    return false;
+  }
+
+  function excluded(element, role) {
+    if (excludedRoles.has(role))
+      return true;
+    for (const sel of filter.selectors || []) {
+      try {
+        if (element.matches(sel))
+          return true;
+      } catch (e) {
+        // An invalid selector excludes nothing.
+      }
+    }
+    for (const area of filter.areas || []) {
+      if (matchesArea(element, role, area))
+        return true;
+    }
+    return false;
+  }
+
+  // childrenOf pierces open shadow roots: a host renders its shadow tree,
+  // and slots render their assigned light-DOM content.
+  function childrenOf(element) {
+    if (element.shadowRoot)
+      return Array.from(element.shadowRoot.children);
+    if (element.tagName === 'SLOT')
+      return element.assignedElements();
+    return Array.from(element.children);
+  }
+
+  function capture(root) {
+    const lines = [];
+    function visit(element, depth) {
+      if (!isVisible(element))
+        return;
+      const role = roleOf(element);
+      // Exclusion drops the whole subtree, not just the node.
+      if (excluded(element, role))
+        return;
+      const children = childrenOf(element);
+      const name = nameOf(element);
+      const interactable = isInteractable(element, role);
+      // Generic unnamed wrappers add depth without information; fold them.
+      if (role === 'generic' && !interactable) {
+        visitChildren(children, depth);
+        return;
+      }
+      let line = '  '.repeat(depth) + '- ' + role;
+      if (name)
+        line += ' "' + name + '"';
+      const states = statesOf(element);
+      if (states.length)
+        line += ' [' + states.join('] [') + ']';
+      if (interactable)
+        line += ' [ref=' + refFor(element, role, name) + ']';
+      if (geometry) {
+        const rect = element.getBoundingClientRect();
+        line += ' [box=' + Math.round(rect.x) + ',' + Math.round(rect.y) +
+            ',' + Math.round(rect.width) + 'x' + Math.round(rect.height) + ']';
+        if (rect.bottom <= 0 || rect.right <= 0 ||
+            rect.top >= innerHeight || rect.left >= innerWidth)
+          line += ' [offscreen]';
+      }
+      lines.push(line);
+      visitChildren(children, depth + 1);
+    }
+    // visitChildren compresses runs of structurally similar siblings
+    // (product grids, infinite lists): the first few render normally, the
+    // rest collapse into one summary line. The collapsed items still get
+    // refs, so the parent can be expanded with browser_snapshot_expand.
+    const sampleSize = 3;
+    function shapeOf(element) {
+      return roleOf(element) + '/' +
+          Array.from(element.children).map((child) => roleOf(child)).join(',');
+    }
+    function visitChildren(children, depth) {
+      let i = 0;
+      while (i < children.length) {
+        const shape = shapeOf(children[i]);
+        let j = i;
+        while (j < children.length && shapeOf(children[j]) === shape)
+          j++;
+        if (j - i <= sampleSize + 2) {
+          for (let k = i; k < j; k++)
+            visit(children[k], depth);
+          i = j;
+          continue;
+        }
+        for (let k = i; k < i + sampleSize; k++)
+          visit(children[k], depth);
+        const refs = [];
+        for (let k = i + sampleSize; k < j; k++) {
+          const element = children[k];
+          refs.push(refFor(element, roleOf(element), nameOf(element)));
+        }
+        lines.push('  '.repeat(depth) + '- × ' + refs.length + ' more similar ' +
+            roleOf(children[i]) + ' items (refs ' + refs[0] + '–' + refs[refs.length - 1] + ')');
+        i = j;
+      }
+    }
+    visit(root, 0);
+    return lines.join('\n');
+  }
+  // Expansion (see snapshotSubtreeScript) re-captures from an arbitrary root.
+  registry.capture = capture;
+  if (selector) {
+    const root = document.querySelector(selector);
+    if (!root)
+      throw new Error('no element matches selector "' + selector + '"');
+    return capture(root);
+  }
+  registry.dirty = false;
+  let out = document.body ? capture(document.body) : '';
+  if (geometry) {
+    out = '- viewport ' + innerWidth + 'x' + innerHeight +
+        ' [scroll=' + Math.round(scrollX) + ',' + Math.round(scrollY) + ']\n' + out;
+  }
+  return out;
+}`
+
+// snapshotDirtyScript reports whether the DOM mutated since the last full
+// capture in this frame.
+const snapshotDirtyScript = `(() => {
+  const registry = window.__mcpRefs;
+  return !registry || registry.dirty !== false;
+})()`
+
+// SnapshotFilter drops nodes from snapshots by ARIA role, CSS selector or
+// boilerplate area ("ads", "cookie-banners", "nav", "footer", "hidden").
+// Excluding a node excludes its whole subtree.
+type SnapshotFilter struct {
+	Roles     []string `json:"roles,omitempty"`
+	Selectors []string `json:"selectors,omitempty"`
+	Areas     []string `json:"areas,omitempty"`
+}
+
+func (f *SnapshotFilter) empty() bool {
+	return f == nil || len(f.Roles)+len(f.Selectors)+len(f.Areas) == 0
+}
+
+// SetSnapshotFilter installs the tab's default filter, applied to every
+// snapshot that does not carry its own.
+func (t *Tab) SetSnapshotFilter(filter SnapshotFilter) {
+	t.mu.Lock()
+	t.filter = filter
+	t.mu.Unlock()
+}
+
+// Snapshot captures the ARIA-style outline of the page, including the
+// content of child frames. Refs embedded in the outline stay valid until
+// the page navigates; frame content carries frame-qualified refs (f1e23)
+// that interaction tools resolve to the right frame.
+func (t *Tab) Snapshot() (string, error) {
+	t.mu.Lock()
+	cached := t.cachedSnapshot
+	t.mu.Unlock()
+	if cached != "" && !t.snapshotDirty() {
+		return cached, nil
+	}
+	text, err := t.capture("", nil, false)
+	if err != nil {
+		return "", err
+	}
+	text += t.childFrameSnapshots(nil, false)
+	t.mu.Lock()
+	t.cachedSnapshot = text
+	t.lastSnapshot = strings.Split(text, "\n")
+	t.mu.Unlock()
+	return text, nil
+}
+
+// snapshotDirty reports whether any frame's DOM mutated since the cached
+// snapshot was captured. Frames that cannot be asked count as dirty.
+func (t *Tab) snapshotDirty() bool {
+	for _, frame := range t.page.Frames() {
+		value, err := frame.Evaluate(snapshotDirtyScript)
+		if err != nil {
+			return true
+		}
+		if dirty, ok := value.(bool); !ok || dirty {
+			return true
+		}
+	}
+	return false
+}
+
+// childFrameSnapshots captures each child frame's outline, namespacing its
+// refs with the frame's index. Frames that cannot be evaluated (detached,
+// cross-origin without CDP access) are skipped rather than failing the
+// whole snapshot.
+func (t *Tab) childFrameSnapshots(filter *SnapshotFilter, geometry bool) string {
+	main := t.page.MainFrame()
+	var b strings.Builder
+	index := 0
+	for _, frame := range t.page.Frames() {
+		if frame == main {
+			continue
+		}
+		index++
+		value, err := frame.Evaluate(snapshotScript, t.snapshotArgs("", filter, geometry))
+		if err != nil {
+			continue
+		}
+		content, _ := value.(string)
+		if content == "" {
+			continue
+		}
+		prefix := fmt.Sprintf("f%d", index)
+		b.WriteString(fmt.Sprintf("\n- iframe \"%s\" [frame=%s]:\n", frame.URL(), prefix))
+		b.WriteString(indentLines(namespaceRefs(content, prefix), 1))
+	}
+	return b.String()
+}
+
+// namespaceRefs rewrites frame-local refs (e12) into frame-qualified ones
+// (f1e12).
+func namespaceRefs(text, prefix string) string {
+	return strings.ReplaceAll(text, "[ref=e", "[ref="+prefix+"e")
+}
+
+func indentLines(text string, levels int) string {
+	indent := strings.Repeat("  ", levels)
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = indent + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// frameRefPattern matches frame-qualified refs like f2e15;
+// framePrefixPattern matches a bare frame prefix like f2.
+var frameRefPattern = regexp.MustCompile(`^f(\d+)(e\d+)$`)
+var framePrefixPattern = regexp.MustCompile(`^f(\d+)$`)
+
+// frameForRef returns the frame a ref belongs to and the frame-local ref.
+// Unqualified refs belong to the main frame.
+func (t *Tab) frameForRef(ref string) (playwright.Frame, string, error) {
+	m := frameRefPattern.FindStringSubmatch(ref)
+	if m == nil {
+		return t.page.MainFrame(), ref, nil
+	}
+	want, _ := strconv.Atoi(m[1])
+	main := t.page.MainFrame()
+	index := 0
+	for _, frame := range t.page.Frames() {
+		if frame == main {
+			continue
+		}
+		index++
+		if index == want {
+			return frame, m[2], nil
+		}
+	}
+	return nil, "", fmt.Errorf("ref %s: frame f%d no longer exists; capture a fresh snapshot", ref, want)
+}
+
+// FrameInfo describes one frame of the page for the frames listing. Ref is
+// the snapshot prefix ("f1", "f2", …), empty for the main frame.
+type FrameInfo struct {
+	Ref  string
+	Name string
+	URL  string
+}
+
+// Frames lists the page's frames in snapshot order, main frame first.
+func (t *Tab) Frames() []FrameInfo {
+	main := t.page.MainFrame()
+	infos := []FrameInfo{{Name: main.Name(), URL: main.URL()}}
+	index := 0
+	for _, frame := range t.page.Frames() {
+		if frame == main {
+			continue
+		}
+		index++
+		infos = append(infos, FrameInfo{Ref: fmt.Sprintf("f%d", index), Name: frame.Name(), URL: frame.URL()})
+	}
+	return infos
+}
+
+// FrameBySpec resolves a tool's frame argument: a snapshot prefix like f2,
+// a frame name, or a URL glob pattern. Empty picks the main frame.
+func (t *Tab) FrameBySpec(spec string) (playwright.Frame, error) {
+	if spec == "" {
+		return t.page.MainFrame(), nil
+	}
+	if framePrefixPattern.MatchString(spec) {
+		frame, _, err := t.frameForRef(spec + "e0")
+		return frame, err
+	}
+	main := t.page.MainFrame()
+	for _, frame := range t.page.Frames() {
+		if frame == main {
+			continue
+		}
+		if frame.Name() != "" && frame.Name() == spec {
+			return frame, nil
+		}
+		if wildcardMatch(spec, frame.URL()) || strings.Contains(frame.URL(), spec) {
+			return frame, nil
+		}
+	}
+	return nil, fmt.Errorf("no frame matches %q (use browser_frames to list them)", spec)
+}
+
+// ariaSnapshotScript renders the page in the shape of Playwright's
+// ariaSnapshot() YAML: role and quoted accessible name per node, heading
+// levels as [level=N], bare text as "text:" leaves, and no refs. Nodes
+// without a role pass their children through, like the aria tree does.
+const ariaSnapshotScript = `() => {
+  const registry = window.__mcpRefs;
+  if (!registry || !registry.roleOf)
+    throw new Error('no snapshot captured yet; call browser_snapshot first');
+  const lines = [];
+  const emit = (depth, text) => lines.push('  '.repeat(depth) + '- ' + text);
+  const visit = (node, depth) => {
+    if (node.nodeType === Node.TEXT_NODE) {
+      const text = node.textContent.trim();
+      if (text)
+        emit(depth, 'text: ' + text);
+      return;
+    }
+    if (node.nodeType !== Node.ELEMENT_NODE || !registry.isVisible(node))
+      return;
+    const children = [];
+    if (node.shadowRoot)
+      children.push(...node.shadowRoot.childNodes);
+    children.push(...node.childNodes);
+    const role = registry.roleOf(node);
+    if (!role) {
+      for (const child of children)
+        visit(child, depth);
+      return;
+    }
+    const name = registry.nameOf(node);
+    let line = role;
+    if (name)
+      line += ' "' + name.replace(/"/g, '\\"') + '"';
+    if (role === 'heading') {
+      const level = node.getAttribute('aria-level') || (/^h([1-6])$/i.exec(node.tagName) || [])[1];
+      if (level)
+        line += ' [level=' + level + ']';
+    }
+    // Leaf roles render their name, not their text children.
+    const leaf = ['link', 'button', 'heading', 'checkbox', 'radio', 'textbox', 'img', 'option'].includes(role);
+    if (leaf) {
+      emit(depth, line);
+      return;
+    }
+    emit(depth, line + ':');
+    for (const child of children)
+      visit(child, depth + 1);
+  };
+  if (document.body)
+    visit(document.body, 0);
+  return lines.join('\n');
+}`
+
+// AriaSnapshot returns the page outline in the shape of Playwright's
+// ariaSnapshot() YAML, paste-able into toMatchAriaSnapshot assertions. It
+// carries no refs; use the default format to interact with elements. The
+// pinned playwright-go has no ariaSnapshot binding, so the outline is
+// produced by the injected registry instead.
+func (t *Tab) AriaSnapshot() (string, error) {
+	// The role/name helpers are installed by the snapshot script; a cached
+	// snapshot makes this a no-op.
+	if _, err := t.Snapshot(); err != nil {
+		return "", err
+	}
+	value, err := t.page.Evaluate(ariaSnapshotScript)
+	if err != nil {
+		return "", fmt.Errorf("failed to capture aria snapshot: %w", err)
+	}
+	text, _ := value.(string)
+	return text, nil
+}
+
+// SnapshotWith captures a full-page snapshot with a one-off filter instead
+// of the tab's default. It does not move the diff baseline.
+func (t *Tab) SnapshotWith(filter *SnapshotFilter) (string, error) {
+	return t.capture("", filter, false)
+}
+
+// SnapshotGeometry captures a full-page snapshot annotated with per-node
+// bounding boxes, viewport visibility and the page's scroll offset, for
+// correlating the outline with screenshots. It does not move the diff
+// baseline.
+func (t *Tab) SnapshotGeometry() (string, error) {
+	text, err := t.capture("", nil, true)
+	if err != nil {
+		return "", err
+	}
+	return text + t.childFrameSnapshots(nil, true), nil
+}
+
+// capture runs the snapshot script in the main frame with the given scope
+// and filter.
+func (t *Tab) capture(selector string, filter *SnapshotFilter, geometry bool) (string, error) {
+	value, err := t.page.Evaluate(snapshotScript, t.snapshotArgs(selector, filter, geometry))
+	if err != nil {
+		return "", fmt.Errorf("failed to capture snapshot: %w", err)
+	}
+	text, _ := value.(string)
+	return text, nil
+}
+
+// snapshotArgs builds the snapshot script's argument object; a nil filter
+// falls back to the tab's default.
+func (t *Tab) snapshotArgs(selector string, filter *SnapshotFilter, geometry bool) map[string]any {
+	if filter == nil {
+		t.mu.Lock()
+		defaults := t.filter
+		t.mu.Unlock()
+		filter = &defaults
+	}
+	args := map[string]any{}
+	if selector != "" {
+		args["selector"] = selector
+	}
+	if !filter.empty() {
+		args["filter"] = filter
+	}
+	if geometry {
+		args["geometry"] = true
+	}
+	return args
+}
+
+// SnapshotDiff captures a snapshot and returns only the lines that changed
+// relative to the previous snapshot of this tab. The first snapshot of a
+// tab has no baseline and is returned in full.
+func (t *Tab) SnapshotDiff() (string, error) {
+	t.mu.Lock()
+	previous := t.lastSnapshot
+	t.mu.Unlock()
+	text, err := t.Snapshot()
+	if err != nil {
+		return "", err
+	}
+	if previous == nil {
+		return text, nil
+	}
+	diff := diffLines(previous, strings.Split(text, "\n"))
+	if len(diff) == 0 {
+		return "No changes since the previous snapshot.", nil
+	}
+	return strings.Join(diff, "\n"), nil
+}
+
+// diffLines computes a longest-common-subsequence diff, rendering removed
+// lines with a "- " prefix and added lines with "+ ". Unchanged lines are
+// omitted — refs for them are in the previous snapshot already.
+func diffLines(old, new []string) []string {
+	lcs := make([][]int, len(old)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(new)+1)
+	}
+	for i := len(old) - 1; i >= 0; i-- {
+		for j := len(new) - 1; j >= 0; j-- {
+			if old[i] == new[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var diff []string
+	i, j := 0, 0
+	for i < len(old) && j < len(new) {
+		switch {
+		case old[i] == new[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, "- "+old[i])
+			i++
+		default:
+			diff = append(diff, "+ "+new[j])
+			j++
+		}
+	}
+	for ; i < len(old); i++ {
+		diff = append(diff, "- "+old[i])
+	}
+	for ; j < len(new); j++ {
+		diff = append(diff, "+ "+new[j])
+	}
+	return diff
+}
+
+// snapshotSubtreeScript captures the outline under a single element, for
+// expanding subtrees that a truncated snapshot omitted.
+const snapshotSubtreeScript = `(ref) => {
+  const registry = window.__mcpRefs;
+  if (!registry || !registry.capture)
+    throw new Error('no snapshot captured yet; call browser_snapshot first');
+  const element = registry.byRef.get(ref);
+  if (!element || !element.isConnected)
+    throw new Error('stale or unknown ref "' + ref + '"; capture a fresh snapshot');
+  return registry.capture(element);
+}`
+
+// SnapshotScoped captures the outline of just the subtree matching a CSS
+// selector. Scoped captures do not move the diff baseline.
+func (t *Tab) SnapshotScoped(selector string) (string, error) {
+	return t.capture(selector, nil, false)
+}
+
+// SnapshotSubtree captures the outline rooted at the element a ref points
+// to, re-resolving the ref first if the DOM changed. Frame-qualified refs
+// are captured in their frame, with the output namespaced the same way.
+func (t *Tab) SnapshotSubtree(ref string) (string, error) {
+	if err := t.ResolveRef(ref); err != nil {
+		return "", err
+	}
+	frame, local, err := t.frameForRef(ref)
+	if err != nil {
+		return "", err
+	}
+	value, err := frame.Evaluate(snapshotSubtreeScript, local)
+	if err != nil {
+		return "", fmt.Errorf("failed to capture subtree: %w", err)
+	}
+	text, _ := value.(string)
+	if local != ref {
+		text = namespaceRefs(text, ref[:len(ref)-len(local)])
+	}
+	return text, nil
+}
+
+// TruncateSnapshot bounds a snapshot to roughly maxChars by pruning the
+// deepest subtrees first. Each pruned subtree is replaced by a marker naming
+// how many nodes were omitted and, when the parent carries a ref, where to
+// point browser_snapshot_expand to get them back.
+func TruncateSnapshot(text string, maxChars int) string {
+	if maxChars <= 0 || len(text) <= maxChars {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	maxDepth := 0
+	for _, line := range lines {
+		if d := lineDepth(line); d > maxDepth {
+			maxDepth = d
+		}
+	}
+	for depth := maxDepth; depth > 0; depth-- {
+		lines = pruneDepth(lines, depth)
+		if size := len(strings.Join(lines, "\n")); size <= maxChars {
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// lineDepth returns the nesting depth of an outline line (two spaces per
+// level).
+func lineDepth(line string) int {
+	indent := len(line) - len(strings.TrimLeft(line, " "))
+	return indent / 2
+}
+
+// pruneDepth removes all lines at the given depth and deeper, leaving one
+// omission marker per parent.
+func pruneDepth(lines []string, depth int) []string {
+	var out []string
+	for i := 0; i < len(lines); i++ {
+		if lineDepth(lines[i]) < depth {
+			out = append(out, lines[i])
+			continue
+		}
+		omitted := 0
+		for i < len(lines) && lineDepth(lines[i]) >= depth {
+			omitted++
+			i++
+		}
+		i--
+		parent := ""
+		if len(out) > 0 {
+			parent = out[len(out)-1]
+		}
+		marker := fmt.Sprintf("%s- %d nodes omitted", strings.Repeat("  ", depth), omitted)
+		if ref := refOfLine(parent); ref != "" {
+			marker += fmt.Sprintf(" under %s (use browser_snapshot_expand)", ref)
+		}
+		out = append(out, marker)
+	}
+	return out
+}
+
+// refOfLine extracts the [ref=eN] value from an outline line, if any.
+func refOfLine(line string) string {
+	start := strings.LastIndex(line, "[ref=")
+	if start < 0 {
+		return ""
+	}
+	rest := line[start+len("[ref="):]
+	end := strings.Index(rest, "]")
+	if end < 0 {
+		return ""
+	}
+	return rest[:end]
+}
+
+// findScript queries visible elements by accessible-name fragment, role
+// and/or attribute values, assigning refs to matches so the caller can act
+// on them without a full snapshot.
+const findScript = `(args) => {
+  const registry = window.__mcpRefs;
+  if (!registry || !registry.refFor)
+    throw new Error('no snapshot captured yet; call browser_snapshot first');
+  const results = [];
+  const limit = args.limit || 20;
+  const text = (args.text || '').toLowerCase();
+  const visit = (node) => {
+    if (results.length >= limit)
+      return;
+    if (!registry.isVisible(node))
+      return;
+    const role = registry.roleOf(node);
+    const name = registry.nameOf(node);
+    let matched = true;
+    if (args.role && role !== args.role)
+      matched = false;
+    if (matched && text && !name.toLowerCase().includes(text))
+      matched = false;
+    if (matched && args.attributes) {
+      for (const key of Object.keys(args.attributes)) {
+        if (node.getAttribute(key) !== args.attributes[key]) {
+          matched = false;
+          break;
+        }
+      }
+    }
+    if (matched)
+      results.push({ref: registry.refFor(node, role, name), role: role, name: name});
+    if (node.shadowRoot) {
+      for (const child of node.shadowRoot.children)
+        visit(child);
+    }
+    for (const child of node.children)
+      visit(child);
+  };
+  if (document.body)
+    visit(document.body);
+  return results;
+}`
+
+// FindMatch is one element matched by FindElements.
+type FindMatch struct {
+	Ref  string
+	Role string
+	Name string
+}
+
+// FindElements returns up to limit visible elements matching a text
+// fragment of the accessible name, an ARIA role and/or exact attribute
+// values. Matches get refs the interaction tools accept.
+func (t *Tab) FindElements(text, role string, attributes map[string]string, limit int) ([]FindMatch, error) {
+	// The ref registry is installed by the snapshot script; a cached
+	// snapshot makes this a no-op.
+	if _, err := t.Snapshot(); err != nil {
+		return nil, err
+	}
+	args := map[string]any{"text": text, "role": role, "limit": limit}
+	if len(attributes) > 0 {
+		args["attributes"] = attributes
+	}
+	value, err := t.page.Evaluate(findScript, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query elements: %w", err)
+	}
+	items, _ := value.([]any)
+	var matches []FindMatch
+	for _, item := range items {
+		fields, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		match := FindMatch{}
+		match.Ref, _ = fields["ref"].(string)
+		match.Role, _ = fields["role"].(string)
+		match.Name, _ = fields["name"].(string)
+		matches = append(matches, match)
+	}
+	return matches, nil
+}
+
+// resolveRefScript checks that a ref still points at a live element. When
+// the element was replaced by a re-render, it re-resolves best-effort by
+// the element's last-known role and accessible name and rebinds the ref,
+// so multi-step plans survive minor DOM churn; only an ambiguous or
+// failed re-resolution surfaces as a stale-ref error.
+const resolveRefScript = `(ref) => {
+  const registry = window.__mcpRefs;
+  if (!registry)
+    throw new Error('no snapshot captured yet; call browser_snapshot first');
+  const element = registry.byRef.get(ref);
+  if (element && element.isConnected)
+    return true;
+  const meta = registry.meta.get(ref);
+  if (!meta)
+    throw new Error('unknown ref "' + ref + '"; capture a fresh snapshot');
+  const matches = [];
+  const visit = (node) => {
+    if (registry.roleOf(node) === meta.role && registry.nameOf(node) === meta.name)
+      matches.push(node);
+    // Pierce open shadow roots so re-resolution finds web-component content.
+    if (node.shadowRoot) {
+      for (const child of node.shadowRoot.children)
+        visit(child);
+    }
+    for (const child of node.children)
+      visit(child);
+  };
+  visit(document.body);
+  if (matches.length === 1) {
+    registry.byRef.set(ref, matches[0]);
+    registry.byElement.set(matches[0], ref);
+    return true;
+  }
+  throw new Error('stale ref "' + ref + '": the element (' + meta.role +
+      ' "' + meta.name + '") is gone and ' + matches.length +
+      ' candidates match it now; capture a fresh snapshot');
+}`
+
+// refMetaScript returns the role and accessible name recorded for a ref.
+const refMetaScript = `(ref) => {
+  const registry = window.__mcpRefs;
+  return (registry && registry.meta.get(ref)) || null;
+}`
+
+// RefMeta returns the role and accessible name a ref was captured with,
+// used to generate getByRole locator code. Unknown refs yield empty
+// strings.
+func (t *Tab) RefMeta(ref string) (role, name string) {
+	frame, local, err := t.frameForRef(ref)
+	if err != nil {
+		return "", ""
+	}
+	value, err := frame.Evaluate(refMetaScript, local)
+	if err != nil {
+		return "", ""
+	}
+	fields, _ := value.(map[string]any)
+	role, _ = fields["role"].(string)
+	name, _ = fields["name"].(string)
+	return role, name
+}
+
+// ResolveRef validates a snapshot ref, re-resolving it by role and name if
+// the DOM changed underneath it. Frame-qualified refs resolve in their
+// frame.
+func (t *Tab) ResolveRef(ref string) error {
+	frame, local, err := t.frameForRef(ref)
+	if err != nil {
+		return err
+	}
+	if _, err := frame.Evaluate(resolveRefScript, local); err != nil {
+		return fmt.Errorf("ref %s: %w", ref, err)
+	}
+	return nil
+}
+
+// RefHandle resolves a snapshot ref into an element handle for tools that
+// act on elements.
+func (t *Tab) RefHandle(ref string) (playwright.ElementHandle, error) {
+	if err := t.ResolveRef(ref); err != nil {
+		return nil, err
+	}
+	frame, local, err := t.frameForRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	handle, err := frame.EvaluateHandle(`ref => window.__mcpRefs.byRef.get(ref)`, local)
+	if err != nil {
+		return nil, fmt.Errorf("ref %s: %w", ref, err)
+	}
+	element := handle.AsElement()
+	if element == nil {
+		return nil, fmt.Errorf("ref %s does not resolve to an element", ref)
+	}
+	return element, nil
+}
@@ -0,0 +1,127 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package browser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		old  []string
+		new  []string
+		want []string
+	}{
+		{
+			name: "identical",
+			old:  []string{"a", "b"},
+			new:  []string{"a", "b"},
+			want: nil,
+		},
+		{
+			name: "changed line",
+			old:  []string{"a", "b", "c"},
+			new:  []string{"a", "x", "c"},
+			want: []string{"- b", "+ x"},
+		},
+		{
+			name: "appended lines",
+			old:  []string{"a"},
+			new:  []string{"a", "b", "c"},
+			want: []string{"+ b", "+ c"},
+		},
+		{
+			name: "removed lines",
+			old:  []string{"a", "b", "c"},
+			new:  []string{"b"},
+			want: []string{"- a", "- c"},
+		},
+		{
+			name: "everything new",
+			old:  nil,
+			new:  []string{"a"},
+			want: []string{"+ a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffLines(tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffLines(%v, %v) = %v, want %v", tt.old, tt.new, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateSnapshot(t *testing.T) {
+	deep := `    - text "` + strings.Repeat("x", 80) + `"`
+	text := strings.Join([]string{
+		`- root [ref=e1]`,
+		`  - child a [ref=e2]`,
+		deep,
+		deep,
+		`  - child b`,
+	}, "\n")
+
+	if got := TruncateSnapshot(text, len(text)); got != text {
+		t.Errorf("snapshot within the limit should be returned verbatim, got %q", got)
+	}
+	if got := TruncateSnapshot(text, 0); got != text {
+		t.Errorf("maxChars 0 disables truncation, got %q", got)
+	}
+
+	got := TruncateSnapshot(text, 130)
+	want := strings.Join([]string{
+		`- root [ref=e1]`,
+		`  - child a [ref=e2]`,
+		`    - 2 nodes omitted under e2 (use browser_snapshot_expand)`,
+		`  - child b`,
+	}, "\n")
+	if got != want {
+		t.Errorf("TruncateSnapshot(text, 130) = %q, want %q", got, want)
+	}
+}
+
+func TestNamespaceRefs(t *testing.T) {
+	in := `- button "Save" [ref=e4]`
+	want := `- button "Save" [ref=f2e4]`
+	if got := namespaceRefs(in, "f2"); got != want {
+		t.Errorf("namespaceRefs(%q, %q) = %q, want %q", in, "f2", got, want)
+	}
+}
+
+func TestFrameRefPatterns(t *testing.T) {
+	m := frameRefPattern.FindStringSubmatch("f2e15")
+	if m == nil || m[1] != "2" || m[2] != "e15" {
+		t.Errorf("frameRefPattern did not split f2e15 into frame and ref: %v", m)
+	}
+	if frameRefPattern.MatchString("e15") {
+		t.Error("frameRefPattern should not match an unqualified ref")
+	}
+	if !framePrefixPattern.MatchString("f3") {
+		t.Error("framePrefixPattern should match a bare frame prefix")
+	}
+	if framePrefixPattern.MatchString("f3e1") {
+		t.Error("framePrefixPattern should not match a frame-qualified ref")
+	}
+}
+
+func TestRefOfLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{`- button "Save" [ref=e4]`, "e4"},
+		{`- link "Home" [ref=f1e12]`, "f1e12"},
+		{`- heading "Title" [level=2]`, ""},
+		{``, ""},
+	}
+	for _, tt := range tests {
+		if got := refOfLine(tt.line); got != tt.want {
+			t.Errorf("refOfLine(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,507 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package browser
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// maxBufferedEntries caps the per-tab console, error and request buffers;
+// the oldest entries are dropped first. Long-lived tabs on chatty pages
+// must not grow without bound.
+const maxBufferedEntries = 1000
+
+// ConsoleMessage is one buffered console entry.
+type ConsoleMessage struct {
+	Type string
+	Text string
+	Time time.Time
+}
+
+// RequestEntry is one entry of a tab's request log. Status is zero until a
+// response arrives; Failure is set when the request never got one. ID is
+// stable for the lifetime of the tab and can be fed back to tools that
+// retrieve headers and bodies.
+type RequestEntry struct {
+	ID      int
+	Method  string
+	URL     string
+	Status  int
+	Failure string
+}
+
+// ModalState describes a blocking browser-side modal the agent must clear
+// before other tools can run. Exactly one of Dialog and FileChooser is set.
+type ModalState struct {
+	Type        string
+	Description string
+	Dialog      playwright.Dialog
+	FileChooser playwright.FileChooser
+}
+
+// TabHooks are the callbacks a Tab's owner provides: the owning context
+// adopts popups as new tabs and drops closed tabs from its list.
+type TabHooks struct {
+	Popup  func(playwright.Page)
+	Closed func(*Tab)
+}
+
+// Tab wraps one playwright.Page with the bookkeeping the MCP tools need:
+// console and page-error history, a request log, captured modal states and
+// popup tracking. All buffers are safe for concurrent access; Playwright
+// delivers events on its own goroutines.
+type Tab struct {
+	page  playwright.Page
+	hooks TabHooks
+
+	mu      sync.Mutex
+	console []ConsoleMessage
+	// consoleTotal counts every message ever buffered and consoleTaken how
+	// many the agent has already seen, so "only new" requests survive the
+	// buffer trimming old entries.
+	consoleTotal int
+	consoleTaken int
+	pageErrors   []string
+	// requests and requestKeys stay aligned: the key at i issued the entry
+	// at i, letting responses and failures find their entry.
+	requests    []*RequestEntry
+	requestKeys []playwright.Request
+	// nextRequestID numbers log entries; ids survive buffer trimming.
+	nextRequestID int
+	modalStates   []ModalState
+	// inflight counts requests without a response yet, for post-action
+	// network-quiescence waiting.
+	inflight int
+	// lastSnapshot holds the lines of the most recent full snapshot, the
+	// baseline for snapshot diffs; cachedSnapshot is its text, reused while
+	// the page's MutationObserver reports no changes.
+	lastSnapshot   []string
+	cachedSnapshot string
+	// filter is the default snapshot exclusion filter, from configuration.
+	filter SnapshotFilter
+	// dialogPolicy decides dialogs automatically; the zero value routes
+	// every dialog to the model as a modal state.
+	dialogPolicy DialogPolicy
+}
+
+// DialogPolicy decides JavaScript dialogs without a round trip to the
+// model. Default applies when no rule matches: "accept", "dismiss" or ""
+// (manual).
+type DialogPolicy struct {
+	Default string
+	Rules   []DialogRule
+}
+
+// DialogRule maps a wildcard pattern on the dialog message onto an action.
+type DialogRule struct {
+	Pattern string
+	Action  string
+}
+
+// SetDialogPolicy installs the tab's automatic dialog policy.
+func (t *Tab) SetDialogPolicy(policy DialogPolicy) {
+	t.mu.Lock()
+	t.dialogPolicy = policy
+	t.mu.Unlock()
+}
+
+// dialogAction resolves the action for a dialog message: the first matching
+// rule wins, then the policy default. "manual" and "" both mean the dialog
+// surfaces as a modal state.
+func (t *Tab) dialogAction(message string) string {
+	t.mu.Lock()
+	policy := t.dialogPolicy
+	t.mu.Unlock()
+	for _, rule := range policy.Rules {
+		if wildcardMatch(rule.Pattern, message) {
+			return rule.Action
+		}
+	}
+	return policy.Default
+}
+
+// NewTab wraps a page and attaches its event listeners. The page may
+// already have history; only events from now on are buffered.
+func NewTab(page playwright.Page, hooks TabHooks) *Tab {
+	t := &Tab{
+		page:  page,
+		hooks: hooks,
+	}
+	page.OnConsole(func(message playwright.ConsoleMessage) {
+		t.mu.Lock()
+		t.console = appendBounded(t.console, ConsoleMessage{Type: message.Type(), Text: message.Text(), Time: time.Now()})
+		t.consoleTotal++
+		t.mu.Unlock()
+	})
+	page.OnPageError(func(err error) {
+		t.mu.Lock()
+		t.pageErrors = appendBounded(t.pageErrors, err.Error())
+		t.mu.Unlock()
+	})
+	page.OnRequest(func(request playwright.Request) {
+		t.mu.Lock()
+		if len(t.requests) >= maxBufferedEntries {
+			t.requests = t.requests[1:]
+			t.requestKeys = t.requestKeys[1:]
+		}
+		t.nextRequestID++
+		t.requests = append(t.requests, &RequestEntry{ID: t.nextRequestID, Method: request.Method(), URL: request.URL()})
+		t.requestKeys = append(t.requestKeys, request)
+		t.inflight++
+		t.mu.Unlock()
+	})
+	page.OnResponse(func(response playwright.Response) {
+		t.mu.Lock()
+		if entry := t.entryFor(response.Request()); entry != nil {
+			entry.Status = response.Status()
+		}
+		t.settleRequest()
+		t.mu.Unlock()
+	})
+	page.OnRequestFailed(func(request playwright.Request) {
+		t.mu.Lock()
+		if entry := t.entryFor(request); entry != nil {
+			if failure := request.Failure(); failure != nil {
+				entry.Failure = failure.Error()
+			}
+		}
+		t.settleRequest()
+		t.mu.Unlock()
+	})
+	page.OnDialog(func(dialog playwright.Dialog) {
+		switch t.dialogAction(dialog.Message()) {
+		case "accept":
+			_ = dialog.Accept()
+		case "dismiss":
+			_ = dialog.Dismiss()
+		default:
+			t.pushModalState(ModalState{
+				Type:        "dialog",
+				Description: fmt.Sprintf("%q dialog with message %q", dialog.Type(), dialog.Message()),
+				Dialog:      dialog,
+			})
+		}
+	})
+	page.OnFileChooser(func(chooser playwright.FileChooser) {
+		t.pushModalState(ModalState{
+			Type:        "fileChooser",
+			Description: "file chooser waiting for files",
+			FileChooser: chooser,
+		})
+	})
+	page.OnPopup(func(popup playwright.Page) {
+		if t.hooks.Popup != nil {
+			t.hooks.Popup(popup)
+		}
+	})
+	page.OnClose(func(playwright.Page) {
+		if t.hooks.Closed != nil {
+			t.hooks.Closed(t)
+		}
+	})
+	return t
+}
+
+// settleRequest marks one in-flight request as finished. Responses for
+// requests issued before the tab was wrapped are clamped out. Callers hold
+// t.mu.
+func (t *Tab) settleRequest() {
+	if t.inflight > 0 {
+		t.inflight--
+	}
+}
+
+// WaitForNetworkIdle waits until the tab has no in-flight requests, up to
+// the cap, so a snapshot taken afterwards reflects the page once the
+// action's XHRs have completed. A page that never goes quiet (polling,
+// websockets with keepalive fetches) only costs the cap, never hangs.
+func (t *Tab) WaitForNetworkIdle(cap time.Duration) {
+	deadline := time.Now().Add(cap)
+	for time.Now().Before(deadline) {
+		t.mu.Lock()
+		idle := t.inflight == 0
+		t.mu.Unlock()
+		if idle {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// entryFor finds the log entry for a request, scanning newest-first since
+// responses usually complete recent requests. Callers hold t.mu.
+func (t *Tab) entryFor(request playwright.Request) *RequestEntry {
+	for i := len(t.requestKeys) - 1; i >= 0; i-- {
+		if t.requestKeys[i] == request {
+			return t.requests[i]
+		}
+	}
+	return nil
+}
+
+func appendBounded[T any](buffer []T, entry T) []T {
+	if len(buffer) >= maxBufferedEntries {
+		buffer = buffer[1:]
+	}
+	return append(buffer, entry)
+}
+
+// Page exposes the underlying Playwright page for tools that need raw
+// access.
+func (t *Tab) Page() playwright.Page {
+	return t.page
+}
+
+// NavigateOptions tune a navigation; the zero value waits for load with
+// the default timeout, matching Playwright.
+type NavigateOptions struct {
+	WaitUntil string
+	Referer   string
+	Timeout   time.Duration
+}
+
+// NavigationResult reports where a navigation actually landed.
+type NavigationResult struct {
+	// FinalURL is the URL after redirects; Redirects lists the URLs that
+	// redirected on the way, in order.
+	FinalURL  string
+	Status    int
+	Redirects []string
+}
+
+// Navigate opens the URL and waits for the requested lifecycle state.
+func (t *Tab) Navigate(url string, opts NavigateOptions) (*NavigationResult, error) {
+	options := playwright.PageGotoOptions{}
+	switch opts.WaitUntil {
+	case "":
+	case "load":
+		options.WaitUntil = playwright.WaitUntilStateLoad
+	case "domcontentloaded":
+		options.WaitUntil = playwright.WaitUntilStateDomcontentloaded
+	case "networkidle":
+		options.WaitUntil = playwright.WaitUntilStateNetworkidle
+	case "commit":
+		options.WaitUntil = playwright.WaitUntilStateCommit
+	default:
+		return nil, fmt.Errorf("unknown waitUntil %q (expected load, domcontentloaded, networkidle or commit)", opts.WaitUntil)
+	}
+	if opts.Referer != "" {
+		options.Referer = playwright.String(opts.Referer)
+	}
+	if opts.Timeout > 0 {
+		options.Timeout = playwright.Float(float64(opts.Timeout.Milliseconds()))
+	}
+	response, err := t.page.Goto(url, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to navigate to %s: %w", url, err)
+	}
+	result := &NavigationResult{FinalURL: t.page.URL()}
+	if response != nil {
+		result.Status = response.Status()
+		for request := response.Request().RedirectedFrom(); request != nil; request = request.RedirectedFrom() {
+			result.Redirects = append([]string{request.URL()}, result.Redirects...)
+		}
+	}
+	return result, nil
+}
+
+// GoBack navigates back in the tab's history.
+func (t *Tab) GoBack() error {
+	if _, err := t.page.GoBack(); err != nil {
+		return fmt.Errorf("failed to go back: %w", err)
+	}
+	return nil
+}
+
+// Title returns the page title.
+func (t *Tab) Title() (string, error) {
+	return t.page.Title()
+}
+
+// URL returns the page URL.
+func (t *Tab) URL() string {
+	return t.page.URL()
+}
+
+// ConsoleMessages returns a snapshot of the buffered console history.
+func (t *Tab) ConsoleMessages() []ConsoleMessage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]ConsoleMessage(nil), t.console...)
+}
+
+// NewConsoleMessages returns the console messages buffered since the last
+// call and advances the seen marker. Messages trimmed out of the buffer
+// before being read are lost, matching the buffer's bounded nature.
+func (t *Tab) NewConsoleMessages() []ConsoleMessage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	start := len(t.console) - (t.consoleTotal - t.consoleTaken)
+	if start < 0 {
+		start = 0
+	}
+	t.consoleTaken = t.consoleTotal
+	return append([]ConsoleMessage(nil), t.console[start:]...)
+}
+
+// ClearConsole drops the buffered console history and page errors.
+func (t *Tab) ClearConsole() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.console = nil
+	t.consoleTotal = 0
+	t.consoleTaken = 0
+	t.pageErrors = nil
+}
+
+// PageErrors returns a snapshot of the buffered uncaught page errors.
+func (t *Tab) PageErrors() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.pageErrors...)
+}
+
+// Requests returns a snapshot of the request log.
+func (t *Tab) Requests() []RequestEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entries := make([]RequestEntry, len(t.requests))
+	for i, entry := range t.requests {
+		entries[i] = *entry
+	}
+	return entries
+}
+
+// FindRequest looks up a recorded request by id, or — when id is zero — by
+// the most recent log entry whose URL contains substr. It returns the log
+// entry along with the live Playwright request for header and body access.
+func (t *Tab) FindRequest(id int, substr string) (RequestEntry, playwright.Request, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i := len(t.requests) - 1; i >= 0; i-- {
+		entry := t.requests[i]
+		if id != 0 && entry.ID != id {
+			continue
+		}
+		if id == 0 && !strings.Contains(entry.URL, substr) {
+			continue
+		}
+		return *entry, t.requestKeys[i], nil
+	}
+	if id != 0 {
+		return RequestEntry{}, nil, fmt.Errorf("no recorded request with id %d (the log keeps the last %d requests)", id, maxBufferedEntries)
+	}
+	return RequestEntry{}, nil, fmt.Errorf("no recorded request matches %q", substr)
+}
+
+// ModalStates returns the modals currently blocking the tab.
+func (t *Tab) ModalStates() []ModalState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]ModalState(nil), t.modalStates...)
+}
+
+func (t *Tab) pushModalState(state ModalState) {
+	t.mu.Lock()
+	t.modalStates = append(t.modalStates, state)
+	t.mu.Unlock()
+}
+
+// ClearModalState removes the first modal of the given type, returning it
+// so the caller can accept, dismiss or feed it. It fails when no such
+// modal is blocking the tab.
+func (t *Tab) ClearModalState(modalType string) (ModalState, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, state := range t.modalStates {
+		if state.Type == modalType {
+			t.modalStates = append(t.modalStates[:i], t.modalStates[i+1:]...)
+			return state, nil
+		}
+	}
+	return ModalState{}, fmt.Errorf("no %q modal is blocking this tab", modalType)
+}
+
+// scrollScript scrolls the window or a ref'd container and returns the new
+// scroll offset.
+const scrollScript = `(args) => {
+  let target = null;
+  if (args.ref) {
+    const registry = window.__mcpRefs;
+    if (!registry)
+      throw new Error('no snapshot captured yet; call browser_snapshot first');
+    target = registry.byRef.get(args.ref);
+    if (!target || !target.isConnected)
+      throw new Error('stale or unknown ref "' + args.ref + '"; capture a fresh snapshot');
+  }
+  if (args.to === 'top') {
+    if (target) target.scrollTop = 0; else scrollTo(0, 0);
+  } else if (args.to === 'bottom') {
+    if (target) target.scrollTop = target.scrollHeight;
+    else scrollTo(0, document.documentElement.scrollHeight);
+  } else {
+    if (target) {
+      target.scrollLeft += args.deltaX || 0;
+      target.scrollTop += args.deltaY || 0;
+    } else {
+      scrollBy(args.deltaX || 0, args.deltaY || 0);
+    }
+  }
+  if (target)
+    return {x: Math.round(target.scrollLeft), y: Math.round(target.scrollTop)};
+  return {x: Math.round(scrollX), y: Math.round(scrollY)};
+}`
+
+// Scroll scrolls the page, or the container ref points at, by a delta or
+// to the top/bottom, and returns the resulting scroll offset.
+func (t *Tab) Scroll(ref, to string, deltaX, deltaY int) (x, y int, err error) {
+	frame := t.page.MainFrame()
+	local := ref
+	if ref != "" {
+		if err := t.ResolveRef(ref); err != nil {
+			return 0, 0, err
+		}
+		frame, local, err = t.frameForRef(ref)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	value, err := frame.Evaluate(scrollScript, map[string]any{
+		"ref":    local,
+		"to":     to,
+		"deltaX": deltaX,
+		"deltaY": deltaY,
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to scroll: %w", err)
+	}
+	fields, _ := value.(map[string]any)
+	return asInt(fields["x"]), asInt(fields["y"]), nil
+}
+
+// asInt converts the numeric types Evaluate may return.
+func asInt(value any) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// IsClosed reports whether the underlying page has gone away.
+func (t *Tab) IsClosed() bool {
+	return t.page.IsClosed()
+}
+
+// Close closes the page; the owner's Closed hook fires as a result.
+func (t *Tab) Close() error {
+	return t.page.Close()
+}
@@ -0,0 +1,898 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FullConfig is the fully resolved server configuration: defaults, config
+// file and CLI options merged in that order.
+type FullConfig struct {
+	Browser      BrowserConfig  `json:"browser,omitempty"`
+	Server       ServerConfig   `json:"server,omitempty"`
+	Capabilities []string       `json:"capabilities,omitempty"`
+	Network      NetworkConfig  `json:"network,omitempty"`
+	Timeouts     TimeoutsConfig `json:"timeouts,omitempty"`
+	Snapshot     SnapshotConfig `json:"snapshot,omitempty"`
+	Dialogs      DialogsConfig  `json:"dialogs,omitempty"`
+	// AllowedTools, when non-empty, is the only set of tools listed and
+	// callable; BlockedTools removes tools from whatever is allowed. Blocks
+	// win over allows.
+	AllowedTools   []string `json:"allowedTools,omitempty"`
+	BlockedTools   []string `json:"blockedTools,omitempty"`
+	OutputDir      string   `json:"outputDir,omitempty"`
+	ImageResponses string   `json:"imageResponses,omitempty"`
+	SaveTrace      bool     `json:"saveTrace,omitempty"`
+	// SaveSession writes an auditable log of every tool call to
+	// OutputDir/session.md and session.jsonl.
+	SaveSession bool `json:"saveSession,omitempty"`
+	// SaveVideo, when set, records each page at the given resolution; webm
+	// files are finalized under OutputDir/videos when the context closes.
+	SaveVideo *ViewportSize `json:"saveVideo,omitempty"`
+	// SaveHar records the session's network traffic into
+	// OutputDir/session.har, finalized when the context closes.
+	SaveHar bool `json:"saveHar,omitempty"`
+	// SaveHarContent controls response-body capture in the HAR: embed
+	// (default), attach or omit.
+	SaveHarContent string `json:"saveHarContent,omitempty"`
+	// ReplayHar serves matching requests from a recorded HAR file instead
+	// of the network, for deterministic offline runs; unmatched requests
+	// are aborted.
+	ReplayHar string `json:"replayHar,omitempty"`
+	// ReplayHarUpdate records missing entries into the HAR instead of
+	// aborting them, refreshing the recording while replaying.
+	ReplayHarUpdate bool `json:"replayHarUpdate,omitempty"`
+	// SecretsFile points to a name=value file; the values are typed via
+	// secret references and masked in all output.
+	SecretsFile string `json:"secretsFile,omitempty"`
+	// AllowedUploadDir restricts browser_file_upload to files under this
+	// directory. Empty allows any absolute path.
+	AllowedUploadDir string `json:"allowedUploadDir,omitempty"`
+	// OutputRetention bounds how much old run output is kept around.
+	OutputRetention RetentionConfig `json:"outputRetention,omitempty"`
+}
+
+// BrowserConfig selects and configures the browser to drive.
+type BrowserConfig struct {
+	BrowserName    string `json:"browserName,omitempty"`
+	Channel        string `json:"channel,omitempty"`
+	Headless       bool   `json:"headless,omitempty"`
+	ExecutablePath string `json:"executablePath,omitempty"`
+	UserDataDir    string `json:"userDataDir,omitempty"`
+	CDPEndpoint    string `json:"cdpEndpoint,omitempty"`
+	// ConnectWS drives a browser hosted on a remote Playwright server
+	// (ws:// or wss://); ConnectWSHeaders pass authentication through.
+	ConnectWS        string            `json:"connectWs,omitempty"`
+	ConnectWSHeaders map[string]string `json:"connectWsHeaders,omitempty"`
+	// Extension routes browser control through the companion Chrome
+	// extension instead of launching a browser.
+	Extension bool `json:"extension,omitempty"`
+	Isolated  bool `json:"isolated,omitempty"`
+	// AutoInstall downloads the browser automatically when a launch fails
+	// because it was never installed.
+	AutoInstall bool `json:"autoInstall,omitempty"`
+	// ForceProfile launches against a temporary copy of the profile when
+	// another browser already holds its lock.
+	ForceProfile bool `json:"forceProfile,omitempty"`
+	// StorageState seeds isolated contexts with cookies and local storage
+	// from a previous run; SaveStorageState writes the final state back on
+	// close (to StorageState, or OutputDir/storage-state.json when unset).
+	StorageState     string `json:"storageState,omitempty"`
+	SaveStorageState bool   `json:"saveStorageState,omitempty"`
+	Device           string `json:"device,omitempty"`
+	ProxyServer      string `json:"proxyServer,omitempty"`
+	// InitScripts are JavaScript files registered via AddInitScript on every
+	// new context, so they run in each page before any of its own scripts.
+	InitScripts    []string       `json:"initScripts,omitempty"`
+	ContextOptions ContextOptions `json:"contextOptions,omitempty"`
+}
+
+// ContextOptions maps onto Playwright browser-context options.
+type ContextOptions struct {
+	Viewport  *ViewportSize `json:"viewport,omitempty"`
+	UserAgent string        `json:"userAgent,omitempty"`
+	// ExtraHTTPHeaders are sent with every request the context makes, e.g.
+	// for staging environments behind header-based routing.
+	ExtraHTTPHeaders map[string]string `json:"extraHTTPHeaders,omitempty"`
+	// Locale is a BCP 47 tag such as "de-DE"; TimezoneID an IANA name such
+	// as "Europe/Berlin".
+	Locale     string `json:"locale,omitempty"`
+	TimezoneID string `json:"timezoneId,omitempty"`
+	// ColorScheme emulates the prefers-color-scheme media feature: "light",
+	// "dark" or "no-preference".
+	ColorScheme string       `json:"colorScheme,omitempty"`
+	Geolocation *Geolocation `json:"geolocation,omitempty"`
+	// DeviceScaleFactor, IsMobile and HasTouch are usually filled in from a
+	// device descriptor rather than set by hand.
+	DeviceScaleFactor float64 `json:"deviceScaleFactor,omitempty"`
+	IsMobile          bool    `json:"isMobile,omitempty"`
+	HasTouch          bool    `json:"hasTouch,omitempty"`
+	// Permissions are granted to every context up front, so pages that
+	// prompt (geolocation, clipboard, ...) don't stall headless runs.
+	Permissions []string `json:"permissions,omitempty"`
+	// HTTPCredentials answers HTTP Basic/Digest auth challenges, which
+	// otherwise surface as a browser-native modal no tool can dismiss.
+	HTTPCredentials *HTTPCredentials `json:"httpCredentials,omitempty"`
+	// ClientCertificates are offered to origins that require mutual TLS.
+	ClientCertificates []ClientCertificate `json:"clientCertificates,omitempty"`
+	// Proxy routes this context's traffic, independent of the launch-level
+	// --proxy-server. Credentials answer proxy auth challenges.
+	Proxy *ProxyConfig `json:"proxy,omitempty"`
+}
+
+// ProxyConfig is a per-context egress proxy.
+type ProxyConfig struct {
+	Server   string `json:"server"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// Bypass is a comma-separated list of hosts that skip the proxy.
+	Bypass string `json:"bypass,omitempty"`
+}
+
+// ClientCertificate is one mTLS client certificate, scoped to an origin.
+// Provide either a PEM cert/key pair or a PFX bundle.
+type ClientCertificate struct {
+	Origin     string `json:"origin"`
+	CertPath   string `json:"certPath,omitempty"`
+	KeyPath    string `json:"keyPath,omitempty"`
+	PfxPath    string `json:"pfxPath,omitempty"`
+	Passphrase string `json:"passphrase,omitempty"`
+}
+
+// HTTPCredentials are the username and password offered on HTTP auth
+// challenges.
+type HTTPCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Geolocation is the position reported to pages that query the geolocation
+// API (the permission still has to be granted).
+type Geolocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// Context modes for server.contextMode.
+const (
+	// ContextModePerClient gives every MCP connection its own isolated
+	// browser context.
+	ContextModePerClient = "per-client"
+	// ContextModeShared gives all connections the same context and tabs,
+	// e.g. for a human co-piloting an agent session.
+	ContextModeShared = "shared"
+)
+
+// ViewportSize is a fixed viewport in CSS pixels.
+type ViewportSize struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// ServerConfig configures the optional HTTP transport. A nil Port means
+// stdio only; an explicit 0 binds an ephemeral port.
+type ServerConfig struct {
+	Host string `json:"host,omitempty"`
+	Port *int   `json:"port,omitempty"`
+	// PortFile, when set, receives the resolved server URL once the
+	// listener is bound, so wrapper scripts can discover an ephemeral port
+	// without racing.
+	PortFile string `json:"portFile,omitempty"`
+	// AuthToken protects all HTTP endpoints when set; clients must present
+	// it as a bearer token.
+	AuthToken string `json:"authToken,omitempty"`
+	// AllowedOrigins are the web origins allowed to open WebSocket
+	// connections to this server. Distinct from network.allowedOrigins,
+	// which restricts what the browser may load. Empty means same-host
+	// only; "*" disables the check.
+	AllowedOrigins []string `json:"allowedOrigins,omitempty"`
+	// ShutdownGrace is how long in-flight tool calls get to finish during
+	// shutdown before browsers are closed anyway.
+	ShutdownGrace Duration `json:"shutdownGrace,omitempty"`
+	// SessionIdleTimeout closes a session's browser context when no tool
+	// call arrives within the window. Zero disables the timeout.
+	SessionIdleTimeout Duration `json:"sessionIdleTimeout,omitempty"`
+	// MaxSessions caps concurrently open browser contexts. Zero means
+	// unlimited.
+	MaxSessions int `json:"maxSessions,omitempty"`
+	// WarmContexts keeps this many pre-launched isolated contexts ready so
+	// new sessions skip browser startup. Only meaningful with isolated mode.
+	WarmContexts int `json:"warmContexts,omitempty"`
+	// ContextMode decides whether MCP connections share one browser
+	// context (and its tabs) or each get their own.
+	ContextMode string `json:"contextMode,omitempty"`
+	// PathPrefix mounts all HTTP routes under the given prefix (e.g.
+	// "/playwright") for deployments behind shared ingress.
+	PathPrefix string `json:"pathPrefix,omitempty"`
+	// LogFormat selects "json" (default) or "console" log output.
+	LogFormat string `json:"logFormat,omitempty"`
+}
+
+// TimeoutsConfig bounds how long Playwright operations may run. Action
+// covers input and locator operations (click, fill, ...); Navigation covers
+// page loads and reloads.
+type TimeoutsConfig struct {
+	Action     Duration `json:"action,omitempty"`
+	Navigation Duration `json:"navigation,omitempty"`
+}
+
+// RetentionConfig limits how much output from previous runs survives under
+// the default output root. A zero value disables the corresponding limit.
+type RetentionConfig struct {
+	// MaxAge removes run directories older than this.
+	MaxAge Duration `json:"maxAge,omitempty"`
+	// MaxTotalSizeMB caps the combined size of kept run directories.
+	MaxTotalSizeMB int64 `json:"maxTotalSizeMb,omitempty"`
+	// MaxRuns caps how many previous run directories are kept.
+	MaxRuns int `json:"maxRuns,omitempty"`
+}
+
+// NetworkConfig restricts which origins the browser may talk to.
+type NetworkConfig struct {
+	AllowedOrigins []string `json:"allowedOrigins,omitempty"`
+	BlockedOrigins []string `json:"blockedOrigins,omitempty"`
+}
+
+// DialogsConfig decides what happens to JavaScript dialogs (alert, confirm,
+// prompt, beforeunload).
+type DialogsConfig struct {
+	// Policy is "manual" (default: dialogs surface as modal states and
+	// block tools until handled), "auto-accept" or "auto-dismiss".
+	Policy string `json:"policy,omitempty"`
+	// Rules override the policy for dialogs whose message matches a
+	// wildcard pattern; the first matching rule wins.
+	Rules []DialogRule `json:"rules,omitempty"`
+}
+
+// DialogRule maps a dialog-message pattern onto an action.
+type DialogRule struct {
+	// Pattern is matched against the dialog message; * matches any run of
+	// characters.
+	Pattern string `json:"pattern"`
+	// Action is "accept", "dismiss" or "manual".
+	Action string `json:"action"`
+}
+
+// SnapshotConfig bounds and shapes accessibility snapshots.
+type SnapshotConfig struct {
+	// MaxChars truncates snapshots beyond this size, pruning the deepest
+	// subtrees first and leaving an "N nodes omitted" marker; pruned
+	// subtrees can be fetched on demand with browser_snapshot_expand.
+	// Zero means unlimited.
+	MaxChars int `json:"maxChars,omitempty"`
+	// ExcludeRoles drops nodes with these ARIA roles (and their subtrees)
+	// from snapshots; ExcludeSelectors drops nodes matching these CSS
+	// selectors. ExcludeAreas drops common boilerplate by heuristic: "ads",
+	// "cookie-banners", "nav", "footer" or "hidden" (aria-hidden content).
+	// browser_snapshot accepts per-call overrides for all three.
+	ExcludeRoles     []string `json:"excludeRoles,omitempty"`
+	ExcludeSelectors []string `json:"excludeSelectors,omitempty"`
+	ExcludeAreas     []string `json:"excludeAreas,omitempty"`
+}
+
+// CLIOptions carries the raw command-line flags before they are merged into
+// a FullConfig.
+type CLIOptions struct {
+	Browser        string
+	Channel        string
+	Headless       bool
+	ExecutablePath string
+	UserDataDir    string
+	CDPEndpoint    string
+	ConnectWS      string
+	// ConnectWSHeaders holds raw "Name: value" strings from repeated
+	// --connect-ws-header flags.
+	ConnectWSHeaders []string
+	Extension        bool
+	Isolated         bool
+	AutoInstall      bool
+	ForceProfile     bool
+	StorageState     string
+	SaveStorageState bool
+	Device           string
+	ProxyServer      string
+	InitScripts      []string
+	// Headers holds raw "Name: value" strings from repeated --header flags.
+	Headers     []string
+	Locale      string
+	Timezone    string
+	ColorScheme string
+	// Geolocation is the raw "lat,lng" string from --geolocation.
+	Geolocation string
+	// HTTPCredentials is the raw "user:pass" string from --http-credentials.
+	HTTPCredentials    string
+	Host               string
+	Port               int
+	PortSet            bool
+	PortFile           string
+	AuthToken          string
+	AllowedWSOrigins   []string
+	ShutdownGrace      time.Duration
+	SessionIdleTimeout time.Duration
+	MaxSessions        int
+	WarmContexts       int
+	ContextMode        string
+	PathPrefix         string
+	LogFormat          string
+	Config             string
+	AllowedTools       []string
+	BlockedTools       []string
+	Capabilities       []string
+	OutputDir          string
+	ViewportSize       string
+	SaveTrace          bool
+	SaveSession        bool
+	// SaveVideo is the raw "WIDTHxHEIGHT" string from --save-video.
+	SaveVideo                string
+	SaveHar                  bool
+	SaveHarContent           string
+	ReplayHar                string
+	ReplayHarUpdate          bool
+	SecretsFile              string
+	AllowedUploadDir         string
+	Dialogs                  string
+	SnapshotMaxChars         int
+	SnapshotExcludeRoles     []string
+	SnapshotExcludeSelectors []string
+	SnapshotExcludeAreas     []string
+	TimeoutAction            time.Duration
+	TimeoutNavigation        time.Duration
+}
+
+// DefaultConfig returns the built-in defaults, including a fresh timestamped
+// output directory under the system temp dir.
+func DefaultConfig() *FullConfig {
+	return &FullConfig{
+		Browser: BrowserConfig{
+			BrowserName: "chromium",
+		},
+		Server: ServerConfig{
+			ShutdownGrace: Duration(15 * time.Second),
+			ContextMode:   ContextModePerClient,
+		},
+		Timeouts: TimeoutsConfig{
+			Action:     Duration(5 * time.Second),
+			Navigation: Duration(60 * time.Second),
+		},
+		Capabilities:   []string{"core", "tabs"},
+		OutputDir:      filepath.Join(os.TempDir(), "playwright-mcp-output", time.Now().Format("2006-01-02T15-04-05")),
+		ImageResponses: "auto",
+		OutputRetention: RetentionConfig{
+			MaxAge:  Duration(72 * time.Hour),
+			MaxRuns: 20,
+		},
+	}
+}
+
+// Load resolves the effective configuration from defaults, the optional
+// config file and CLI options, in increasing precedence.
+func Load(cliOptions *CLIOptions) (*FullConfig, error) {
+	cfg := DefaultConfig()
+	if cliOptions.Config != "" {
+		fileConfig, err := loadConfig(cliOptions.Config)
+		if err != nil {
+			return nil, err
+		}
+		mergeConfig(cfg, fileConfig)
+	}
+	cliConfig, err := configFromCLIOptions(cliOptions)
+	if err != nil {
+		return nil, err
+	}
+	mergeConfig(cfg, cliConfig)
+	if err := cfg.applyDevice(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// loadConfig reads a config file from disk. JSON files may carry comments
+// and trailing commas; .yaml/.yml files are parsed as YAML.
+func loadConfig(path string) (*FullConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	var cfg FullConfig
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		// Round-trip through JSON so the json struct tags stay the single
+		// source of truth for field names.
+		var raw any
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+		jsonData, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert config file %s: %w", path, err)
+		}
+		if err := unmarshalStrict(jsonData, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	default:
+		if err := unmarshalStrict(sanitizeJSONC(data), &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+	return &cfg, nil
+}
+
+// unmarshalStrict is json.Unmarshal that rejects unknown keys, so a typo in
+// a config file fails loudly instead of being silently ignored.
+func unmarshalStrict(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// configFromCLIOptions lifts CLI flags into a partial FullConfig.
+func configFromCLIOptions(opts *CLIOptions) (*FullConfig, error) {
+	headers, err := parseHeaderFlags(opts.Headers)
+	if err != nil {
+		return nil, err
+	}
+	geolocation, err := parseGeolocationFlag(opts.Geolocation)
+	if err != nil {
+		return nil, err
+	}
+	httpCredentials, err := parseHTTPCredentialsFlag(opts.HTTPCredentials)
+	if err != nil {
+		return nil, err
+	}
+	connectWSHeaders, err := parseHeaderFlags(opts.ConnectWSHeaders)
+	if err != nil {
+		return nil, err
+	}
+	var saveVideo *ViewportSize
+	if opts.SaveVideo != "" {
+		saveVideo, err = ParseViewport(opts.SaveVideo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --save-video: %w", err)
+		}
+	}
+	cfg := &FullConfig{
+		Browser: BrowserConfig{
+			Channel:          opts.Channel,
+			Headless:         opts.Headless,
+			ExecutablePath:   opts.ExecutablePath,
+			UserDataDir:      opts.UserDataDir,
+			CDPEndpoint:      opts.CDPEndpoint,
+			ConnectWS:        opts.ConnectWS,
+			ConnectWSHeaders: connectWSHeaders,
+			Extension:        opts.Extension,
+			Isolated:         opts.Isolated,
+			AutoInstall:      opts.AutoInstall,
+			ForceProfile:     opts.ForceProfile,
+			StorageState:     opts.StorageState,
+			SaveStorageState: opts.SaveStorageState,
+			Device:           opts.Device,
+			ProxyServer:      opts.ProxyServer,
+			InitScripts:      opts.InitScripts,
+		},
+		Server: ServerConfig{
+			Host:               opts.Host,
+			PortFile:           opts.PortFile,
+			AuthToken:          opts.AuthToken,
+			AllowedOrigins:     opts.AllowedWSOrigins,
+			ShutdownGrace:      Duration(opts.ShutdownGrace),
+			SessionIdleTimeout: Duration(opts.SessionIdleTimeout),
+			MaxSessions:        opts.MaxSessions,
+			WarmContexts:       opts.WarmContexts,
+			ContextMode:        opts.ContextMode,
+			PathPrefix:         opts.PathPrefix,
+			LogFormat:          opts.LogFormat,
+		},
+		Timeouts: TimeoutsConfig{
+			Action:     Duration(opts.TimeoutAction),
+			Navigation: Duration(opts.TimeoutNavigation),
+		},
+		Snapshot: SnapshotConfig{
+			MaxChars:         opts.SnapshotMaxChars,
+			ExcludeRoles:     opts.SnapshotExcludeRoles,
+			ExcludeSelectors: opts.SnapshotExcludeSelectors,
+			ExcludeAreas:     opts.SnapshotExcludeAreas,
+		},
+		Dialogs: DialogsConfig{
+			Policy: opts.Dialogs,
+		},
+		AllowedTools:     opts.AllowedTools,
+		BlockedTools:     opts.BlockedTools,
+		Capabilities:     opts.Capabilities,
+		OutputDir:        opts.OutputDir,
+		SaveTrace:        opts.SaveTrace,
+		SaveSession:      opts.SaveSession,
+		SaveVideo:        saveVideo,
+		SaveHar:          opts.SaveHar,
+		SaveHarContent:   opts.SaveHarContent,
+		ReplayHar:        opts.ReplayHar,
+		ReplayHarUpdate:  opts.ReplayHarUpdate,
+		SecretsFile:      opts.SecretsFile,
+		AllowedUploadDir: opts.AllowedUploadDir,
+	}
+	if opts.PortSet {
+		port := opts.Port
+		cfg.Server.Port = &port
+	}
+	cfg.Browser.ContextOptions.ExtraHTTPHeaders = headers
+	cfg.Browser.ContextOptions.Locale = opts.Locale
+	cfg.Browser.ContextOptions.TimezoneID = opts.Timezone
+	cfg.Browser.ContextOptions.ColorScheme = opts.ColorScheme
+	cfg.Browser.ContextOptions.Geolocation = geolocation
+	cfg.Browser.ContextOptions.HTTPCredentials = httpCredentials
+	switch opts.Browser {
+	case "chrome", "chrome-beta", "chrome-canary", "chrome-dev", "msedge", "msedge-beta", "msedge-canary", "msedge-dev":
+		cfg.Browser.BrowserName = "chromium"
+		cfg.Browser.Channel = opts.Browser
+	case "chromium", "firefox", "webkit":
+		cfg.Browser.BrowserName = opts.Browser
+	}
+	return cfg, nil
+}
+
+// parseGeolocationFlag parses the --geolocation "lat,lng" flag.
+func parseGeolocationFlag(raw string) (*Geolocation, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	lat, lng, ok := strings.Cut(raw, ",")
+	if !ok {
+		return nil, fmt.Errorf("invalid --geolocation %q (expected \"lat,lng\", e.g. \"52.52,13.40\")", raw)
+	}
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --geolocation latitude %q: %v", lat, err)
+	}
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(lng), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --geolocation longitude %q: %v", lng, err)
+	}
+	return &Geolocation{Latitude: latitude, Longitude: longitude}, nil
+}
+
+// parseHTTPCredentialsFlag parses the --http-credentials "user:pass" flag.
+func parseHTTPCredentialsFlag(raw string) (*HTTPCredentials, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	username, password, ok := strings.Cut(raw, ":")
+	if !ok || username == "" {
+		return nil, fmt.Errorf("invalid --http-credentials (expected \"user:pass\")")
+	}
+	return &HTTPCredentials{Username: username, Password: password}, nil
+}
+
+// parseHeaderFlags parses repeated --header "Name: value" flags.
+func parseHeaderFlags(raw []string) (map[string]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	headers := make(map[string]string, len(raw))
+	for _, entry := range raw {
+		name, value, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --header %q (expected \"Name: value\")", entry)
+		}
+		headers[name] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+// mergeConfig overlays non-zero fields of overrides onto base.
+func mergeConfig(base, overrides *FullConfig) {
+	mergeBrowserConfig(&base.Browser, &overrides.Browser)
+	if overrides.Server.Host != "" {
+		base.Server.Host = overrides.Server.Host
+	}
+	if overrides.Server.Port != nil {
+		base.Server.Port = overrides.Server.Port
+	}
+	if overrides.Server.PortFile != "" {
+		base.Server.PortFile = overrides.Server.PortFile
+	}
+	if overrides.Server.AuthToken != "" {
+		base.Server.AuthToken = overrides.Server.AuthToken
+	}
+	if len(overrides.Server.AllowedOrigins) > 0 {
+		base.Server.AllowedOrigins = overrides.Server.AllowedOrigins
+	}
+	if overrides.Server.ShutdownGrace != 0 {
+		base.Server.ShutdownGrace = overrides.Server.ShutdownGrace
+	}
+	if overrides.Server.SessionIdleTimeout != 0 {
+		base.Server.SessionIdleTimeout = overrides.Server.SessionIdleTimeout
+	}
+	if overrides.Server.MaxSessions != 0 {
+		base.Server.MaxSessions = overrides.Server.MaxSessions
+	}
+	if overrides.Server.WarmContexts != 0 {
+		base.Server.WarmContexts = overrides.Server.WarmContexts
+	}
+	if overrides.Server.ContextMode != "" {
+		base.Server.ContextMode = overrides.Server.ContextMode
+	}
+	if overrides.Server.PathPrefix != "" {
+		base.Server.PathPrefix = overrides.Server.PathPrefix
+	}
+	if overrides.Server.LogFormat != "" {
+		base.Server.LogFormat = overrides.Server.LogFormat
+	}
+	if overrides.Timeouts.Action != 0 {
+		base.Timeouts.Action = overrides.Timeouts.Action
+	}
+	if overrides.Timeouts.Navigation != 0 {
+		base.Timeouts.Navigation = overrides.Timeouts.Navigation
+	}
+	if overrides.Snapshot.MaxChars != 0 {
+		base.Snapshot.MaxChars = overrides.Snapshot.MaxChars
+	}
+	if len(overrides.Snapshot.ExcludeRoles) > 0 {
+		base.Snapshot.ExcludeRoles = overrides.Snapshot.ExcludeRoles
+	}
+	if len(overrides.Snapshot.ExcludeSelectors) > 0 {
+		base.Snapshot.ExcludeSelectors = overrides.Snapshot.ExcludeSelectors
+	}
+	if len(overrides.Snapshot.ExcludeAreas) > 0 {
+		base.Snapshot.ExcludeAreas = overrides.Snapshot.ExcludeAreas
+	}
+	if overrides.Dialogs.Policy != "" {
+		base.Dialogs.Policy = overrides.Dialogs.Policy
+	}
+	if len(overrides.Dialogs.Rules) > 0 {
+		base.Dialogs.Rules = overrides.Dialogs.Rules
+	}
+	if len(overrides.AllowedTools) > 0 {
+		base.AllowedTools = overrides.AllowedTools
+	}
+	if len(overrides.BlockedTools) > 0 {
+		base.BlockedTools = overrides.BlockedTools
+	}
+	if len(overrides.Capabilities) > 0 {
+		base.Capabilities = overrides.Capabilities
+	}
+	if len(overrides.Network.AllowedOrigins) > 0 {
+		base.Network.AllowedOrigins = overrides.Network.AllowedOrigins
+	}
+	if len(overrides.Network.BlockedOrigins) > 0 {
+		base.Network.BlockedOrigins = overrides.Network.BlockedOrigins
+	}
+	if overrides.OutputDir != "" {
+		base.OutputDir = overrides.OutputDir
+	}
+	if overrides.ImageResponses != "" {
+		base.ImageResponses = overrides.ImageResponses
+	}
+	if overrides.SaveTrace {
+		base.SaveTrace = true
+	}
+	if overrides.SaveSession {
+		base.SaveSession = true
+	}
+	if overrides.SaveVideo != nil {
+		base.SaveVideo = overrides.SaveVideo
+	}
+	if overrides.SaveHar {
+		base.SaveHar = true
+	}
+	if overrides.SaveHarContent != "" {
+		base.SaveHarContent = overrides.SaveHarContent
+	}
+	if overrides.ReplayHar != "" {
+		base.ReplayHar = overrides.ReplayHar
+	}
+	if overrides.ReplayHarUpdate {
+		base.ReplayHarUpdate = true
+	}
+	if overrides.SecretsFile != "" {
+		base.SecretsFile = overrides.SecretsFile
+	}
+	if overrides.AllowedUploadDir != "" {
+		base.AllowedUploadDir = overrides.AllowedUploadDir
+	}
+	if overrides.OutputRetention.MaxAge != 0 {
+		base.OutputRetention.MaxAge = overrides.OutputRetention.MaxAge
+	}
+	if overrides.OutputRetention.MaxTotalSizeMB != 0 {
+		base.OutputRetention.MaxTotalSizeMB = overrides.OutputRetention.MaxTotalSizeMB
+	}
+	if overrides.OutputRetention.MaxRuns != 0 {
+		base.OutputRetention.MaxRuns = overrides.OutputRetention.MaxRuns
+	}
+}
+
+func mergeBrowserConfig(base, overrides *BrowserConfig) {
+	if overrides.BrowserName != "" {
+		base.BrowserName = overrides.BrowserName
+	}
+	if overrides.Channel != "" {
+		base.Channel = overrides.Channel
+	}
+	if overrides.Headless {
+		base.Headless = true
+	}
+	if overrides.ExecutablePath != "" {
+		base.ExecutablePath = overrides.ExecutablePath
+	}
+	if overrides.UserDataDir != "" {
+		base.UserDataDir = overrides.UserDataDir
+	}
+	if overrides.CDPEndpoint != "" {
+		base.CDPEndpoint = overrides.CDPEndpoint
+	}
+	if overrides.ConnectWS != "" {
+		base.ConnectWS = overrides.ConnectWS
+	}
+	if len(overrides.ConnectWSHeaders) > 0 {
+		base.ConnectWSHeaders = overrides.ConnectWSHeaders
+	}
+	if overrides.Extension {
+		base.Extension = true
+	}
+	if overrides.Isolated {
+		base.Isolated = true
+	}
+	if overrides.AutoInstall {
+		base.AutoInstall = true
+	}
+	if overrides.ForceProfile {
+		base.ForceProfile = true
+	}
+	if overrides.StorageState != "" {
+		base.StorageState = overrides.StorageState
+	}
+	if overrides.SaveStorageState {
+		base.SaveStorageState = true
+	}
+	if overrides.Device != "" {
+		base.Device = overrides.Device
+	}
+	if overrides.ProxyServer != "" {
+		base.ProxyServer = overrides.ProxyServer
+	}
+	if len(overrides.InitScripts) > 0 {
+		base.InitScripts = overrides.InitScripts
+	}
+	if overrides.ContextOptions.Viewport != nil {
+		base.ContextOptions.Viewport = overrides.ContextOptions.Viewport
+	}
+	if overrides.ContextOptions.UserAgent != "" {
+		base.ContextOptions.UserAgent = overrides.ContextOptions.UserAgent
+	}
+	if len(overrides.ContextOptions.ExtraHTTPHeaders) > 0 {
+		if base.ContextOptions.ExtraHTTPHeaders == nil {
+			base.ContextOptions.ExtraHTTPHeaders = make(map[string]string)
+		}
+		for name, value := range overrides.ContextOptions.ExtraHTTPHeaders {
+			base.ContextOptions.ExtraHTTPHeaders[name] = value
+		}
+	}
+	if overrides.ContextOptions.Locale != "" {
+		base.ContextOptions.Locale = overrides.ContextOptions.Locale
+	}
+	if overrides.ContextOptions.TimezoneID != "" {
+		base.ContextOptions.TimezoneID = overrides.ContextOptions.TimezoneID
+	}
+	if overrides.ContextOptions.ColorScheme != "" {
+		base.ContextOptions.ColorScheme = overrides.ContextOptions.ColorScheme
+	}
+	if overrides.ContextOptions.Geolocation != nil {
+		base.ContextOptions.Geolocation = overrides.ContextOptions.Geolocation
+	}
+	if overrides.ContextOptions.DeviceScaleFactor != 0 {
+		base.ContextOptions.DeviceScaleFactor = overrides.ContextOptions.DeviceScaleFactor
+	}
+	if overrides.ContextOptions.IsMobile {
+		base.ContextOptions.IsMobile = true
+	}
+	if overrides.ContextOptions.HasTouch {
+		base.ContextOptions.HasTouch = true
+	}
+	if len(overrides.ContextOptions.Permissions) > 0 {
+		base.ContextOptions.Permissions = overrides.ContextOptions.Permissions
+	}
+	if overrides.ContextOptions.HTTPCredentials != nil {
+		base.ContextOptions.HTTPCredentials = overrides.ContextOptions.HTTPCredentials
+	}
+	if len(overrides.ContextOptions.ClientCertificates) > 0 {
+		base.ContextOptions.ClientCertificates = overrides.ContextOptions.ClientCertificates
+	}
+	if overrides.ContextOptions.Proxy != nil {
+		base.ContextOptions.Proxy = overrides.ContextOptions.Proxy
+	}
+}
+
+// DownloadsDir is where page-initiated downloads are saved. Contexts are
+// created with acceptDownloads enabled and every download is moved here.
+func (c *FullConfig) DownloadsDir() string {
+	return filepath.Join(c.OutputDir, "downloads")
+}
+
+// HarPath is where the session's HAR recording is finalized when SaveHar
+// is enabled.
+func (c *FullConfig) HarPath() string {
+	return filepath.Join(c.OutputDir, "session.har")
+}
+
+// TracePath is where the session's Playwright trace is finalized when
+// SaveTrace is enabled.
+func (c *FullConfig) TracePath() string {
+	return filepath.Join(c.OutputDir, "trace.zip")
+}
+
+// VideosDir is where recorded page videos are finalized when SaveVideo is
+// enabled.
+func (c *FullConfig) VideosDir() string {
+	return filepath.Join(c.OutputDir, "videos")
+}
+
+// Redacted returns a copy of the config safe to print or log: secret-bearing
+// fields are masked, not removed, so it stays visible that they are set.
+func (c *FullConfig) Redacted() *FullConfig {
+	clone := *c
+	if clone.Server.AuthToken != "" {
+		clone.Server.AuthToken = "********"
+	}
+	if credentials := clone.Browser.ContextOptions.HTTPCredentials; credentials != nil {
+		masked := *credentials
+		masked.Password = "********"
+		clone.Browser.ContextOptions.HTTPCredentials = &masked
+	}
+	if proxy := clone.Browser.ContextOptions.Proxy; proxy != nil && proxy.Password != "" {
+		masked := *proxy
+		masked.Password = "********"
+		clone.Browser.ContextOptions.Proxy = &masked
+	}
+	if certificates := clone.Browser.ContextOptions.ClientCertificates; len(certificates) > 0 {
+		masked := append([]ClientCertificate(nil), certificates...)
+		for i := range masked {
+			if masked[i].Passphrase != "" {
+				masked[i].Passphrase = "********"
+			}
+		}
+		clone.Browser.ContextOptions.ClientCertificates = masked
+	}
+	return &clone
+}
+
+// ToolAllowed applies the allow/block lists to a tool name. An empty allow
+// list permits everything; the block list always wins.
+func (c *FullConfig) ToolAllowed(name string) bool {
+	for _, blocked := range c.BlockedTools {
+		if blocked == name {
+			return false
+		}
+	}
+	if len(c.AllowedTools) == 0 {
+		return true
+	}
+	for _, allowed := range c.AllowedTools {
+		if allowed == name {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCapability reports whether the named capability is enabled.
+func (c *FullConfig) HasCapability(name string) bool {
+	for _, capability := range c.Capabilities {
+		if capability == name {
+			return true
+		}
+	}
+	return false
+}
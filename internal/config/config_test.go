@@ -0,0 +1,45 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeConfig(t *testing.T) {
+	base := &FullConfig{}
+	base.Browser.BrowserName = "chromium"
+	base.Browser.Channel = "chrome"
+	base.Server.Host = "localhost"
+	base.Capabilities = []string{"core"}
+	base.OutputDir = "/tmp/base"
+
+	overrides := &FullConfig{}
+	overrides.Browser.Channel = "msedge"
+	overrides.Browser.Headless = true
+	overrides.Server.Host = "0.0.0.0"
+	overrides.Capabilities = []string{"core", "vision"}
+
+	mergeConfig(base, overrides)
+
+	if base.Browser.BrowserName != "chromium" {
+		t.Errorf("browserName = %q, unset override must not clear the base", base.Browser.BrowserName)
+	}
+	if base.Browser.Channel != "msedge" {
+		t.Errorf("channel = %q, want the override to win", base.Browser.Channel)
+	}
+	if !base.Browser.Headless {
+		t.Error("headless = false, want the override to win")
+	}
+	if base.Server.Host != "0.0.0.0" {
+		t.Errorf("host = %q, want the override to win", base.Server.Host)
+	}
+	if want := []string{"core", "vision"}; !reflect.DeepEqual(base.Capabilities, want) {
+		t.Errorf("capabilities = %v, want %v", base.Capabilities, want)
+	}
+	if base.OutputDir != "/tmp/base" {
+		t.Errorf("outputDir = %q, unset override must not clear the base", base.OutputDir)
+	}
+}
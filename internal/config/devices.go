@@ -0,0 +1,172 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DeviceDescriptor mirrors one entry of Playwright's device registry
+// (deviceDescriptorsSource.json): enough emulation detail to stand in for
+// the real hardware.
+type DeviceDescriptor struct {
+	UserAgent          string
+	Viewport           ViewportSize
+	DeviceScaleFactor  float64
+	IsMobile           bool
+	HasTouch           bool
+	DefaultBrowserType string
+}
+
+// deviceRegistry is the embedded subset of Playwright's device registry
+// covering the descriptors agents ask for in practice. Entries are keyed by
+// the upstream device name.
+var deviceRegistry = map[string]DeviceDescriptor{
+	"Desktop Chrome": {
+		UserAgent:          "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.6778.33 Safari/537.36",
+		Viewport:           ViewportSize{Width: 1280, Height: 720},
+		DeviceScaleFactor:  1,
+		DefaultBrowserType: "chromium",
+	},
+	"Desktop Chrome HiDPI": {
+		UserAgent:          "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.6778.33 Safari/537.36",
+		Viewport:           ViewportSize{Width: 1280, Height: 720},
+		DeviceScaleFactor:  2,
+		DefaultBrowserType: "chromium",
+	},
+	"Desktop Edge": {
+		UserAgent:          "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.6778.33 Safari/537.36 Edg/131.0.6778.33",
+		Viewport:           ViewportSize{Width: 1280, Height: 720},
+		DeviceScaleFactor:  1,
+		DefaultBrowserType: "chromium",
+	},
+	"Desktop Firefox": {
+		UserAgent:          "Mozilla/5.0 (X11; Linux x86_64; rv:132.0) Gecko/20100101 Firefox/132.0",
+		Viewport:           ViewportSize{Width: 1280, Height: 720},
+		DeviceScaleFactor:  1,
+		DefaultBrowserType: "firefox",
+	},
+	"Desktop Safari": {
+		UserAgent:          "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.0 Safari/605.1.15",
+		Viewport:           ViewportSize{Width: 1280, Height: 720},
+		DeviceScaleFactor:  2,
+		DefaultBrowserType: "webkit",
+	},
+	"Galaxy S24": {
+		UserAgent:          "Mozilla/5.0 (Linux; Android 14; SM-S921B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.6778.33 Mobile Safari/537.36",
+		Viewport:           ViewportSize{Width: 360, Height: 780},
+		DeviceScaleFactor:  3,
+		IsMobile:           true,
+		HasTouch:           true,
+		DefaultBrowserType: "chromium",
+	},
+	"iPad (gen 7)": {
+		UserAgent:          "Mozilla/5.0 (iPad; CPU OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+		Viewport:           ViewportSize{Width: 810, Height: 1080},
+		DeviceScaleFactor:  2,
+		IsMobile:           true,
+		HasTouch:           true,
+		DefaultBrowserType: "webkit",
+	},
+	"iPad Pro 11": {
+		UserAgent:          "Mozilla/5.0 (iPad; CPU OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+		Viewport:           ViewportSize{Width: 834, Height: 1194},
+		DeviceScaleFactor:  2,
+		IsMobile:           true,
+		HasTouch:           true,
+		DefaultBrowserType: "webkit",
+	},
+	"iPhone 15": {
+		UserAgent:          "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+		Viewport:           ViewportSize{Width: 393, Height: 659},
+		DeviceScaleFactor:  3,
+		IsMobile:           true,
+		HasTouch:           true,
+		DefaultBrowserType: "webkit",
+	},
+	"iPhone 15 Pro Max": {
+		UserAgent:          "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+		Viewport:           ViewportSize{Width: 430, Height: 739},
+		DeviceScaleFactor:  3,
+		IsMobile:           true,
+		HasTouch:           true,
+		DefaultBrowserType: "webkit",
+	},
+	"iPhone SE": {
+		UserAgent:          "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+		Viewport:           ViewportSize{Width: 320, Height: 568},
+		DeviceScaleFactor:  2,
+		IsMobile:           true,
+		HasTouch:           true,
+		DefaultBrowserType: "webkit",
+	},
+	"Pixel 7": {
+		UserAgent:          "Mozilla/5.0 (Linux; Android 14; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.6778.33 Mobile Safari/537.36",
+		Viewport:           ViewportSize{Width: 412, Height: 915},
+		DeviceScaleFactor:  2.625,
+		IsMobile:           true,
+		HasTouch:           true,
+		DefaultBrowserType: "chromium",
+	},
+}
+
+// DeviceNames lists all registered device names, sorted.
+func DeviceNames() []string {
+	names := make([]string, 0, len(deviceRegistry))
+	for name := range deviceRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupDevice resolves a device name case-insensitively.
+func LookupDevice(name string) (DeviceDescriptor, bool) {
+	if device, ok := deviceRegistry[name]; ok {
+		return device, true
+	}
+	for registered, device := range deviceRegistry {
+		if strings.EqualFold(registered, name) {
+			return device, true
+		}
+	}
+	return DeviceDescriptor{}, false
+}
+
+// applyDevice resolves browser.device into context options. Explicitly
+// configured options win over the device descriptor, so --device with a
+// custom viewport does what it looks like it does. An unset browser adopts
+// the device's default engine (iPhone implies webkit).
+func (c *FullConfig) applyDevice() error {
+	if c.Browser.Device == "" {
+		return nil
+	}
+	device, ok := LookupDevice(c.Browser.Device)
+	if !ok {
+		return fmt.Errorf("browser.device: unknown device %q (run --list-devices for the registry)", c.Browser.Device)
+	}
+	if c.Browser.BrowserName == "" {
+		c.Browser.BrowserName = device.DefaultBrowserType
+	}
+	opts := &c.Browser.ContextOptions
+	if opts.Viewport == nil {
+		viewport := device.Viewport
+		opts.Viewport = &viewport
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = device.UserAgent
+	}
+	if opts.DeviceScaleFactor == 0 {
+		opts.DeviceScaleFactor = device.DeviceScaleFactor
+	}
+	if !opts.IsMobile {
+		opts.IsMobile = device.IsMobile
+	}
+	if !opts.HasTouch {
+		opts.HasTouch = device.HasTouch
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Duration is a time.Duration that unmarshals from either a duration string
+// ("10s", "2m") or a plain number of milliseconds, the two forms people
+// write in config files.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var asString string
+	if err := json.Unmarshal(data, &asString); err == nil {
+		parsed, err := time.ParseDuration(asString)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", asString, err)
+		}
+		*d = Duration(parsed)
+		return nil
+	}
+	var asMillis float64
+	if err := json.Unmarshal(data, &asMillis); err != nil {
+		return fmt.Errorf("invalid duration: %s", data)
+	}
+	*d = Duration(time.Duration(asMillis) * time.Millisecond)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Std returns the wrapped time.Duration.
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSanitizeJSONC(t *testing.T) {
+	input := []byte(`{
+  // line comment
+  "url": "https://example.com/path", /* inline */
+  "note": "slashes // and /* markers */ stay inside strings",
+  "list": [1, 2, 3,],
+  "nested": {
+    "key": "value",
+  },
+}`)
+	var parsed struct {
+		URL    string         `json:"url"`
+		Note   string         `json:"note"`
+		List   []int          `json:"list"`
+		Nested map[string]any `json:"nested"`
+	}
+	sanitized := sanitizeJSONC(input)
+	if err := json.Unmarshal(sanitized, &parsed); err != nil {
+		t.Fatalf("sanitized output is not valid JSON: %v\n%s", err, sanitized)
+	}
+	if parsed.URL != "https://example.com/path" {
+		t.Errorf("url = %q, want the // inside the string preserved", parsed.URL)
+	}
+	if parsed.Note != "slashes // and /* markers */ stay inside strings" {
+		t.Errorf("note = %q, comment markers inside strings must survive", parsed.Note)
+	}
+	if len(parsed.List) != 3 {
+		t.Errorf("list = %v, want the trailing comma dropped", parsed.List)
+	}
+	if parsed.Nested["key"] != "value" {
+		t.Errorf("nested = %v, want the trailing comma dropped", parsed.Nested)
+	}
+}
+
+func TestSanitizeJSONCEscapedQuote(t *testing.T) {
+	input := []byte(`{"s": "a \" // not a comment"}`)
+	var parsed map[string]string
+	if err := json.Unmarshal(sanitizeJSONC(input), &parsed); err != nil {
+		t.Fatalf("sanitized output is not valid JSON: %v", err)
+	}
+	if parsed["s"] != `a " // not a comment` {
+		t.Errorf("s = %q, escaped quotes must not end the string early", parsed["s"])
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SessionOverrides is the subset of config a client may override per
+// session, via query parameters on the HTTP transports or a "playwright"
+// object in the initialize params. Only emulation-level settings are
+// included on purpose: nothing here may widen network access, touch the
+// filesystem, or escape the sandbox the operator configured.
+type SessionOverrides struct {
+	Viewport     *ViewportSize `json:"viewport,omitempty"`
+	Device       string        `json:"device,omitempty"`
+	UserAgent    string        `json:"userAgent,omitempty"`
+	Capabilities []string      `json:"capabilities,omitempty"`
+	Headless     *bool         `json:"headless,omitempty"`
+	// Proxy is the one deliberate exception to the emulation-only rule: on
+	// multi-tenant servers each session may route through its own egress
+	// proxy. It narrows where traffic goes rather than widening access.
+	Proxy *ProxyConfig `json:"proxy,omitempty"`
+	// BrowserInstance attaches the session to a named managed browser
+	// instance (see the /browsers management API) instead of launching
+	// one. It can only pick instances the operator already started.
+	BrowserInstance string `json:"browserInstance,omitempty"`
+}
+
+// WithSessionOverrides returns a copy of the config with the session's
+// overrides applied.
+func (c *FullConfig) WithSessionOverrides(overrides *SessionOverrides) *FullConfig {
+	if overrides == nil {
+		return c
+	}
+	clone := *c
+	if overrides.Viewport != nil {
+		clone.Browser.ContextOptions.Viewport = overrides.Viewport
+	}
+	if overrides.Device != "" {
+		clone.Browser.Device = overrides.Device
+		// Re-resolve the descriptor for the new device; emulation options
+		// the operator set explicitly still win inside applyDevice.
+		clone.Browser.ContextOptions = c.Browser.ContextOptions
+		clone.applyDevice()
+	}
+	if overrides.UserAgent != "" {
+		clone.Browser.ContextOptions.UserAgent = overrides.UserAgent
+	}
+	if len(overrides.Capabilities) > 0 {
+		clone.Capabilities = overrides.Capabilities
+	}
+	if overrides.Headless != nil {
+		clone.Browser.Headless = *overrides.Headless
+	}
+	if overrides.Proxy != nil {
+		clone.Browser.ContextOptions.Proxy = overrides.Proxy
+	}
+	return &clone
+}
+
+// ParseViewport parses a "WIDTHxHEIGHT" string like "1280x720".
+func ParseViewport(value string) (*ViewportSize, error) {
+	parts := strings.SplitN(value, "x", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid viewport %q, expected WIDTHxHEIGHT", value)
+	}
+	width, errW := strconv.Atoi(strings.TrimSpace(parts[0]))
+	height, errH := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if errW != nil || errH != nil || width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid viewport %q, expected WIDTHxHEIGHT", value)
+	}
+	return &ViewportSize{Width: width, Height: height}, nil
+}
@@ -0,0 +1,192 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KnownCapabilities are the capability names accepted in config and on the
+// command line.
+var KnownCapabilities = []string{
+	"core",
+	"tabs",
+	"install",
+	"pdf",
+	"history",
+	"wait",
+	"files",
+	"evaluate",
+	"vision",
+	"testing",
+}
+
+// Validate checks the fully merged configuration for values that would only
+// fail later, deep inside a browser launch or a tool call. Errors name the
+// offending config path and show a correct example so users can fix the file
+// or flag without reading source.
+func (c *FullConfig) Validate() error {
+	switch c.Browser.BrowserName {
+	case "", "chromium", "firefox", "webkit":
+	default:
+		return fmt.Errorf("browser.browserName: unknown browser %q (expected chromium, firefox or webkit)", c.Browser.BrowserName)
+	}
+	if name := c.Browser.BrowserName; name == "firefox" || name == "webkit" {
+		// Chromium-only features: CDP is a Chromium protocol, the extension
+		// relay drives Chrome, and channels are Chrome/Edge distributions.
+		if c.Browser.CDPEndpoint != "" {
+			return fmt.Errorf("browser.cdpEndpoint requires chromium: %s does not speak CDP", name)
+		}
+		if c.Browser.Extension {
+			return fmt.Errorf("browser.extension requires chromium: the extension relay drives Chrome")
+		}
+		if c.Browser.Channel != "" {
+			return fmt.Errorf("browser.channel %q requires chromium: %s has no distribution channels", c.Browser.Channel, name)
+		}
+		if name == "firefox" && c.Browser.ContextOptions.IsMobile {
+			return fmt.Errorf("browser.contextOptions.isMobile is not supported by firefox; emulate mobile devices with chromium or webkit")
+		}
+	}
+	if c.Browser.Isolated && c.Browser.UserDataDir != "" {
+		return fmt.Errorf("browser.isolated conflicts with browser.userDataDir: isolated sessions use a fresh in-memory profile; drop one of the two options")
+	}
+	if c.Browser.CDPEndpoint != "" && c.Browser.ExecutablePath != "" {
+		return fmt.Errorf("browser.cdpEndpoint conflicts with browser.executablePath: connecting to a running browser and launching one are mutually exclusive")
+	}
+	if c.Browser.ConnectWS != "" && c.Browser.CDPEndpoint != "" {
+		return fmt.Errorf("browser.connectWs conflicts with browser.cdpEndpoint: pick one remote browser mode")
+	}
+	if viewport := c.Browser.ContextOptions.Viewport; viewport != nil {
+		if viewport.Width <= 0 || viewport.Height <= 0 {
+			return fmt.Errorf("browser.contextOptions.viewport: width and height must be positive (e.g. {\"width\": 1280, \"height\": 720})")
+		}
+	}
+	switch c.Browser.ContextOptions.ColorScheme {
+	case "", "light", "dark", "no-preference":
+	default:
+		return fmt.Errorf("browser.contextOptions.colorScheme: unknown value %q (expected light, dark or no-preference)", c.Browser.ContextOptions.ColorScheme)
+	}
+	if geo := c.Browser.ContextOptions.Geolocation; geo != nil {
+		if geo.Latitude < -90 || geo.Latitude > 90 || geo.Longitude < -180 || geo.Longitude > 180 {
+			return fmt.Errorf("browser.contextOptions.geolocation: latitude must be in [-90, 90] and longitude in [-180, 180]")
+		}
+	}
+	for i, certificate := range c.Browser.ContextOptions.ClientCertificates {
+		path := fmt.Sprintf("browser.contextOptions.clientCertificates[%d]", i)
+		if certificate.Origin == "" {
+			return fmt.Errorf("%s.origin: required (e.g. \"https://internal.example.com\")", path)
+		}
+		hasPair := certificate.CertPath != "" && certificate.KeyPath != ""
+		hasPfx := certificate.PfxPath != ""
+		if hasPair == hasPfx {
+			return fmt.Errorf("%s: provide either certPath+keyPath or pfxPath", path)
+		}
+		for _, file := range []string{certificate.CertPath, certificate.KeyPath, certificate.PfxPath} {
+			if file == "" {
+				continue
+			}
+			if _, err := os.Stat(file); err != nil {
+				return fmt.Errorf("%s: cannot read %q: %v", path, file, err)
+			}
+		}
+	}
+	if state := c.Browser.StorageState; state != "" && !c.Browser.SaveStorageState {
+		// With SaveStorageState the file may legitimately not exist yet; it
+		// is created on close.
+		if _, err := os.Stat(state); err != nil {
+			return fmt.Errorf("browser.storageState: cannot read %q: %v", state, err)
+		}
+	}
+	for _, script := range c.Browser.InitScripts {
+		if _, err := os.Stat(script); err != nil {
+			return fmt.Errorf("browser.initScripts: cannot read %q: %v", script, err)
+		}
+	}
+	for _, capability := range c.Capabilities {
+		if !isKnownCapability(capability) {
+			return fmt.Errorf("capabilities: unknown capability %q (expected one of %s)", capability, strings.Join(KnownCapabilities, ", "))
+		}
+	}
+	switch c.ImageResponses {
+	case "", "auto", "allow", "omit":
+	default:
+		return fmt.Errorf("imageResponses: unknown value %q (expected auto, allow or omit)", c.ImageResponses)
+	}
+	switch c.Server.LogFormat {
+	case "", "json", "console":
+	default:
+		return fmt.Errorf("server.logFormat: unknown value %q (expected json or console)", c.Server.LogFormat)
+	}
+	if c.Server.Port != nil && (*c.Server.Port < 0 || *c.Server.Port > 65535) {
+		return fmt.Errorf("server.port: %d is out of range (expected 0-65535, 0 binds an ephemeral port)", *c.Server.Port)
+	}
+	if c.Server.MaxSessions < 0 {
+		return fmt.Errorf("server.maxSessions: must not be negative (0 means unlimited)")
+	}
+	if c.Server.WarmContexts < 0 {
+		return fmt.Errorf("server.warmContexts: must not be negative (0 disables the warm pool)")
+	}
+	switch c.Server.ContextMode {
+	case "", ContextModePerClient, ContextModeShared:
+	default:
+		return fmt.Errorf("server.contextMode: unknown value %q (expected shared or per-client)", c.Server.ContextMode)
+	}
+	if c.Server.WarmContexts > 0 && !c.Browser.Isolated {
+		return fmt.Errorf("server.warmContexts requires browser.isolated: persistent profiles cannot be pre-launched")
+	}
+	if c.ReplayHar != "" && !c.ReplayHarUpdate {
+		// In update mode the file may not exist yet; it is created on close.
+		if _, err := os.Stat(c.ReplayHar); err != nil {
+			return fmt.Errorf("replayHar: cannot read %q: %v", c.ReplayHar, err)
+		}
+	}
+	switch c.SaveHarContent {
+	case "", "embed", "attach", "omit":
+	default:
+		return fmt.Errorf("saveHarContent: unknown value %q (expected embed, attach or omit)", c.SaveHarContent)
+	}
+	if c.SaveVideo != nil && (c.SaveVideo.Width <= 0 || c.SaveVideo.Height <= 0) {
+		return fmt.Errorf("saveVideo: width and height must be positive (e.g. \"800x600\")")
+	}
+	if c.Snapshot.MaxChars < 0 {
+		return fmt.Errorf("snapshot.maxChars: must not be negative (0 means unlimited)")
+	}
+	switch c.Dialogs.Policy {
+	case "", "manual", "auto-accept", "auto-dismiss":
+	default:
+		return fmt.Errorf("dialogs.policy: unknown policy %q (want manual, auto-accept or auto-dismiss)", c.Dialogs.Policy)
+	}
+	for _, rule := range c.Dialogs.Rules {
+		if rule.Pattern == "" {
+			return fmt.Errorf("dialogs.rules: pattern must not be empty")
+		}
+		switch rule.Action {
+		case "accept", "dismiss", "manual":
+		default:
+			return fmt.Errorf("dialogs.rules: unknown action %q for pattern %q (want accept, dismiss or manual)", rule.Action, rule.Pattern)
+		}
+	}
+	for _, area := range c.Snapshot.ExcludeAreas {
+		switch area {
+		case "ads", "cookie-banners", "nav", "footer", "hidden":
+		default:
+			return fmt.Errorf("snapshot.excludeAreas: unknown area %q (want ads, cookie-banners, nav, footer or hidden)", area)
+		}
+	}
+	if c.Timeouts.Action < 0 || c.Timeouts.Navigation < 0 {
+		return fmt.Errorf("timeouts: action and navigation must not be negative (e.g. \"5s\")")
+	}
+	return nil
+}
+
+func isKnownCapability(name string) bool {
+	for _, capability := range KnownCapabilities {
+		if capability == name {
+			return true
+		}
+	}
+	return false
+}
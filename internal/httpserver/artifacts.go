@@ -0,0 +1,42 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package httpserver
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// handleArtifacts serves files from the output directory (screenshots, PDFs,
+// traces, videos) so remote clients can fetch artifacts that tool results
+// reference. Read-only; bearer-token auth applies like on every other
+// endpoint.
+func (s *Server) handleArtifacts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	relative := strings.TrimPrefix(r.URL.Path, s.route("/artifacts/"))
+	full, ok := resolveArtifactPath(s.config.OutputDir, relative)
+	if !ok {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	http.ServeFile(w, r, full)
+}
+
+// resolveArtifactPath joins the requested path onto the output directory and
+// rejects anything that escapes it.
+func resolveArtifactPath(outputDir, relative string) (string, bool) {
+	if relative == "" {
+		return "", false
+	}
+	full := filepath.Join(outputDir, filepath.FromSlash(relative))
+	rel, err := filepath.Rel(outputDir, full)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return full, true
+}
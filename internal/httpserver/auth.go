@@ -0,0 +1,68 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package httpserver
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// authTokenEnv is the environment variable consulted for the bearer token
+// when --auth-token is not given, and re-read on SIGHUP for rotation.
+const authTokenEnv = "PLAYWRIGHT_MCP_TOKEN"
+
+// requireAuth wraps a handler with bearer-token authentication. With no
+// token configured the handler is open; otherwise anyone who can reach the
+// port could drive a browser.
+func (s *Server) requireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Probe endpoints are exempt: orchestrators don't carry bearer
+		// tokens and the probes leak nothing.
+		if r.URL.Path == s.route("/healthz") || r.URL.Path == s.route("/readyz") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		s.tokenMu.RLock()
+		token := s.authToken
+		s.tokenMu.RUnlock()
+		if token != "" && !tokenMatches(r, token) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tokenMatches checks the Authorization header against the configured token
+// in constant time.
+func tokenMatches(r *http.Request, token string) bool {
+	header := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return false
+	}
+	presented := strings.TrimPrefix(header, prefix)
+	return subtle.ConstantTimeCompare([]byte(presented), []byte(token)) == 1
+}
+
+// watchTokenRotation re-reads the token env var on SIGHUP so hosted
+// deployments can rotate credentials without dropping live sessions.
+func (s *Server) watchTokenRotation() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	go func() {
+		for range ch {
+			if token := os.Getenv(authTokenEnv); token != "" {
+				s.tokenMu.Lock()
+				s.authToken = token
+				s.tokenMu.Unlock()
+			}
+		}
+	}()
+}
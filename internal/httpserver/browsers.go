@@ -0,0 +1,56 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// handleBrowsers is the managed-browser instance API:
+//
+//	GET    /browsers        list instances with their CDP ports and PIDs
+//	POST   /browsers?name=x launch a named instance
+//	DELETE /browsers?name=x close an instance
+//
+// Sessions attach to an instance with the browserInstance session override
+// (?instance=x on the HTTP transports).
+func (s *Server) handleBrowsers(w http.ResponseWriter, r *http.Request) {
+	browsers := s.server.Browsers()
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, browsers.List())
+	case http.MethodPost:
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			http.Error(w, "name is required", http.StatusBadRequest)
+			return
+		}
+		info, err := browsers.Launch(name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		writeJSON(w, http.StatusOK, info)
+	case http.MethodDelete:
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			http.Error(w, "name is required", http.StatusBadRequest)
+			return
+		}
+		if err := browsers.Close(name); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload)
+}
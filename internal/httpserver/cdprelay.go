@@ -0,0 +1,95 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// handleCDP relays DevTools protocol traffic between an external client and
+// the managed browser, so tools that speak CDP (including Playwright itself)
+// can attach to the browser this server controls. The relay is transparent:
+// both directions are pumped verbatim, including Target.* attachment
+// traffic, and closing either side tears down the other.
+func (s *Server) handleCDP(w http.ResponseWriter, r *http.Request) {
+	if s.config.Browser.Extension {
+		s.handleExtensionCDP(w, r)
+		return
+	}
+	browserWSURL, err := s.browserCDPURL()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	browserConn, _, err := websocket.DefaultDialer.Dial(browserWSURL, nil)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to connect to browser: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	clientConn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		browserConn.Close()
+		return
+	}
+
+	done := make(chan struct{}, 2)
+	go relayWebSocket(clientConn, browserConn, done)
+	go relayWebSocket(browserConn, clientConn, done)
+	go func() {
+		<-done
+		clientConn.Close()
+		browserConn.Close()
+	}()
+}
+
+// relayWebSocket pumps messages from src to dst until either side fails.
+func relayWebSocket(src, dst *websocket.Conn, done chan struct{}) {
+	defer func() { done <- struct{}{} }()
+	for {
+		messageType, data, err := src.ReadMessage()
+		if err != nil {
+			return
+		}
+		if err := dst.WriteMessage(messageType, data); err != nil {
+			return
+		}
+	}
+}
+
+// browserCDPURL resolves the WebSocket debugger URL of the managed browser.
+func (s *Server) browserCDPURL() (string, error) {
+	endpoint := s.config.Browser.CDPEndpoint
+	if endpoint == "" {
+		return "", fmt.Errorf("no CDP endpoint available: the browser has not been launched with remote debugging")
+	}
+	if strings.HasPrefix(endpoint, "ws://") || strings.HasPrefix(endpoint, "wss://") {
+		return endpoint, nil
+	}
+	// An http(s) endpoint points at the DevTools HTTP interface; ask it for
+	// the browser-level WebSocket URL.
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(strings.TrimRight(endpoint, "/") + "/json/version")
+	if err != nil {
+		return "", fmt.Errorf("failed to query CDP endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+	var version struct {
+		WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&version); err != nil {
+		return "", fmt.Errorf("failed to parse /json/version response: %w", err)
+	}
+	if version.WebSocketDebuggerURL == "" {
+		return "", fmt.Errorf("CDP endpoint did not report a webSocketDebuggerUrl")
+	}
+	return version.WebSocketDebuggerURL, nil
+}
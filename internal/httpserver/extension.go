@@ -0,0 +1,128 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package httpserver
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// extensionRelay bridges CDP clients to a user's already-running Chrome via
+// the companion browser extension. The extension keeps one WebSocket open to
+// /extension and executes CDP commands through chrome.debugger against the
+// tab it is attached to; whatever connects to /cdp is paired with it. This
+// lets the MCP server drive the user's logged-in browser instead of
+// launching a fresh profile.
+type extensionRelay struct {
+	mu        sync.Mutex
+	extension *websocket.Conn
+	client    *websocket.Conn
+}
+
+// handleExtension accepts the companion extension's WebSocket. A newly
+// connecting extension replaces any previous one.
+func (s *Server) handleExtension(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	s.extensionRelay.mu.Lock()
+	if s.extensionRelay.extension != nil {
+		s.extensionRelay.extension.Close()
+	}
+	s.extensionRelay.extension = conn
+	s.extensionRelay.mu.Unlock()
+
+	// Pump extension → client until the extension goes away.
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		s.extensionRelay.mu.Lock()
+		client := s.extensionRelay.client
+		s.extensionRelay.mu.Unlock()
+		if client != nil {
+			if err := client.WriteMessage(messageType, data); err != nil {
+				s.detachExtensionClient(client)
+			}
+		}
+	}
+
+	s.extensionRelay.mu.Lock()
+	if s.extensionRelay.extension == conn {
+		s.extensionRelay.extension = nil
+		if s.extensionRelay.client != nil {
+			s.extensionRelay.client.Close()
+			s.extensionRelay.client = nil
+		}
+	}
+	s.extensionRelay.mu.Unlock()
+	conn.Close()
+}
+
+// handleExtensionCDP pairs a CDP client with the connected extension and
+// relays traffic between them. Only one client is served at a time, matching
+// chrome.debugger's single-debugger-per-tab constraint.
+func (s *Server) handleExtensionCDP(w http.ResponseWriter, r *http.Request) {
+	s.extensionRelay.mu.Lock()
+	extension := s.extensionRelay.extension
+	busy := s.extensionRelay.client != nil
+	s.extensionRelay.mu.Unlock()
+	if extension == nil {
+		http.Error(w, "browser extension is not connected", http.StatusServiceUnavailable)
+		return
+	}
+	if busy {
+		http.Error(w, "another CDP client is already attached", http.StatusConflict)
+		return
+	}
+
+	client, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	s.extensionRelay.mu.Lock()
+	s.extensionRelay.client = client
+	s.extensionRelay.mu.Unlock()
+
+	// Ask the extension to attach chrome.debugger to its current tab before
+	// any client traffic flows.
+	extension.WriteMessage(websocket.TextMessage, []byte(`{"method":"attachToTab"}`))
+
+	// Pump client → extension; the reverse direction is handled by the
+	// extension's read loop.
+	for {
+		messageType, data, err := client.ReadMessage()
+		if err != nil {
+			break
+		}
+		s.extensionRelay.mu.Lock()
+		ext := s.extensionRelay.extension
+		s.extensionRelay.mu.Unlock()
+		if ext == nil {
+			break
+		}
+		if err := ext.WriteMessage(messageType, data); err != nil {
+			break
+		}
+	}
+	s.detachExtensionClient(client)
+}
+
+func (s *Server) detachExtensionClient(client *websocket.Conn) {
+	s.extensionRelay.mu.Lock()
+	if s.extensionRelay.client == client {
+		s.extensionRelay.client = nil
+		if s.extensionRelay.extension != nil {
+			s.extensionRelay.extension.WriteMessage(websocket.TextMessage, []byte(`{"method":"detachFromTab"}`))
+		}
+	}
+	s.extensionRelay.mu.Unlock()
+	client.Close()
+}
@@ -0,0 +1,41 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package httpserver
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// handleHealthz reports process liveness. It answers as long as the process
+// can serve HTTP at all, so orchestrators can tell a hung process from a
+// slow one.
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprintln(w, "ok")
+}
+
+// handleReadyz reports readiness to take new sessions. It returns 503 before
+// the server finished starting up and again once shutdown drain begins, so
+// load balancers stop routing new clients here.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	s.readyMu.RLock()
+	ready, reason := s.ready, s.notReadyReason
+	s.readyMu.RUnlock()
+	w.Header().Set("Content-Type", "text/plain")
+	if !ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, reason)
+		return
+	}
+	fmt.Fprintln(w, "ready")
+}
+
+// setReady flips the readiness state reported by /readyz.
+func (s *Server) setReady(ready bool, reason string) {
+	s.readyMu.Lock()
+	s.ready = ready
+	s.notReadyReason = reason
+	s.readyMu.Unlock()
+}
@@ -0,0 +1,285 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+// Package httpserver exposes the MCP server over HTTP: SSE and WebSocket
+// transports plus a small management API.
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+
+	"github.com/microsoft/playwright-mcp/internal/config"
+	"github.com/microsoft/playwright-mcp/internal/mcp"
+	"github.com/microsoft/playwright-mcp/internal/server"
+)
+
+// keepaliveInterval is how often network transports ping their client; a
+// client that misses one interval is considered gone.
+const keepaliveInterval = 30 * time.Second
+
+// Server serves the MCP protocol and management endpoints over HTTP.
+type Server struct {
+	config   *config.FullConfig
+	server   *server.Server
+	upgrader websocket.Upgrader
+	http     *http.Server
+
+	sseMu       sync.Mutex
+	sseSessions map[string]*sseSession
+
+	tokenMu   sync.RWMutex
+	authToken string
+
+	extensionRelay extensionRelay
+
+	readyMu        sync.RWMutex
+	ready          bool
+	notReadyReason string
+
+	// pathPrefix is prepended to every route and generated URL.
+	pathPrefix string
+
+	log zerolog.Logger
+}
+
+// NewServer creates an HTTP front-end for the given MCP server.
+func NewServer(cfg *config.FullConfig, srv *server.Server) *Server {
+	s := &Server{
+		config:      cfg,
+		server:      srv,
+		sseSessions: make(map[string]*sseSession),
+	}
+	s.upgrader = websocket.Upgrader{CheckOrigin: s.checkOrigin}
+	s.authToken = cfg.Server.AuthToken
+	if s.authToken == "" {
+		s.authToken = os.Getenv(authTokenEnv)
+	}
+	s.pathPrefix = strings.TrimRight(cfg.Server.PathPrefix, "/")
+	s.log = newLogger(cfg.Server.LogFormat)
+	mux := http.NewServeMux()
+	mux.HandleFunc(s.route("/sse"), s.handleSSE)
+	mux.HandleFunc(s.route("/ws"), s.handleWebSocket)
+	mux.HandleFunc(s.route("/cdp"), s.handleCDP)
+	mux.HandleFunc(s.route("/extension"), s.handleExtension)
+	mux.HandleFunc(s.route("/browsers"), s.handleBrowsers)
+	mux.HandleFunc(s.route("/healthz"), s.handleHealthz)
+	mux.HandleFunc(s.route("/readyz"), s.handleReadyz)
+	mux.HandleFunc(s.route("/artifacts/"), s.handleArtifacts)
+	port := 0
+	if cfg.Server.Port != nil {
+		port = *cfg.Server.Port
+	}
+	s.http = &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", cfg.Server.Host, port),
+		Handler: s.withAccessLog(s.requireAuth(mux)),
+	}
+	s.watchTokenRotation()
+	s.startSSEReaper()
+	return s
+}
+
+// route prepends the configured path prefix to an endpoint path.
+func (s *Server) route(path string) string {
+	return s.pathPrefix + path
+}
+
+// ListenAndServe binds the listener (resolving port 0 to a real ephemeral
+// port), announces the resolved URL, then blocks serving HTTP until the
+// server is closed.
+func (s *Server) ListenAndServe() error {
+	listener, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return err
+	}
+	resolvedURL := s.resolvedURL(listener)
+	// Deliberately on stdout: wrapper scripts and test harnesses parse this
+	// line to discover where an ephemeral-port server is listening.
+	fmt.Println(resolvedURL)
+	s.log.Info().Str("address", resolvedURL).Msg("listening")
+	if portFile := s.config.Server.PortFile; portFile != "" {
+		if err := os.WriteFile(portFile, []byte(resolvedURL+"\n"), 0o644); err != nil {
+			listener.Close()
+			return fmt.Errorf("failed to write port file: %w", err)
+		}
+	}
+	s.server.SetArtifactBaseURL(resolvedURL + "/artifacts")
+	if s.config.Browser.Extension {
+		// In extension mode the context factory connects to the user's
+		// Chrome through our own /cdp relay; its address (and the token
+		// needed to pass our auth) are only known once the listener is
+		// bound.
+		port := listener.Addr().(*net.TCPAddr).Port
+		s.config.Browser.CDPEndpoint = fmt.Sprintf("ws://127.0.0.1:%d%s/cdp", port, s.pathPrefix)
+		s.tokenMu.RLock()
+		s.config.Server.AuthToken = s.authToken
+		s.tokenMu.RUnlock()
+	}
+	s.setReady(true, "")
+	err = s.http.Serve(listener)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// resolvedURL renders the externally usable base URL for the bound listener.
+func (s *Server) resolvedURL(listener net.Listener) string {
+	host := s.config.Server.Host
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	return fmt.Sprintf("http://%s%s", net.JoinHostPort(host, fmt.Sprintf("%d", port)), s.pathPrefix)
+}
+
+// Shutdown drains the server: readiness flips off so load balancers stop
+// sending traffic, new sessions are rejected, in-flight tool calls get the
+// grace period to finish, then contexts, browsers and finally the listener
+// are closed.
+func (s *Server) Shutdown(grace time.Duration) error {
+	s.setReady(false, "shutting down")
+	s.server.Drain(grace)
+	ctx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+	return s.http.Shutdown(ctx)
+}
+
+// Close shuts the HTTP server down immediately, without drain.
+func (s *Server) Close() error {
+	s.server.Close()
+	return s.http.Close()
+}
+
+// checkOrigin guards the WebSocket upgrade handshake against cross-site
+// hijacking from web pages. Requests without an Origin header come from
+// non-browser clients and are allowed; browser requests must match the
+// configured allowlist, or the server's own host by default.
+func (s *Server) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	allowed := s.config.Server.AllowedOrigins
+	if len(allowed) == 0 {
+		parsed, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		return strings.EqualFold(parsed.Host, r.Host)
+	}
+	for _, entry := range allowed {
+		if entry == "*" || strings.EqualFold(entry, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// sessionOverridesFromQuery lifts the safe per-session config overrides out
+// of the request's query string, e.g. ?viewport=1280x720&device=iPhone%2015.
+func sessionOverridesFromQuery(query url.Values) (*config.SessionOverrides, error) {
+	overrides := &config.SessionOverrides{
+		Device:          query.Get("device"),
+		UserAgent:       query.Get("userAgent"),
+		BrowserInstance: query.Get("instance"),
+	}
+	empty := overrides.Device == "" && overrides.UserAgent == "" && overrides.BrowserInstance == ""
+	if value := query.Get("viewport"); value != "" {
+		viewport, err := config.ParseViewport(value)
+		if err != nil {
+			return nil, err
+		}
+		overrides.Viewport = viewport
+		empty = false
+	}
+	if value := query.Get("capabilities"); value != "" {
+		overrides.Capabilities = strings.Split(value, ",")
+		empty = false
+	}
+	if value := query.Get("headless"); value != "" {
+		headless := value == "true" || value == "1"
+		overrides.Headless = &headless
+		empty = false
+	}
+	if value := query.Get("proxyServer"); value != "" {
+		overrides.Proxy = &config.ProxyConfig{
+			Server:   value,
+			Username: query.Get("proxyUsername"),
+			Password: query.Get("proxyPassword"),
+		}
+		empty = false
+	}
+	if empty {
+		return nil, nil
+	}
+	return overrides, nil
+}
+
+// handleWebSocket upgrades the connection and runs an MCP session over it.
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	overrides, err := sessionOverridesFromQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	backend, err := s.server.NewConnection(overrides)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	ws, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		backend.Close()
+		return
+	}
+	transport := newWebSocketTransport(ws)
+	conn := mcp.NewConnection(transport, backend, mcp.Implementation{Name: "Playwright"})
+	conn.StartKeepalive(keepaliveInterval)
+	remote := r.RemoteAddr
+	go func() {
+		conn.Run()
+		// Run returns once the transport is gone; the backend and its
+		// browser context were closed on the way out.
+		s.log.Info().Str("remote", remote).Msg("WebSocket client disconnected, session cleaned up")
+	}()
+}
+
+// webSocketTransport adapts a websocket.Conn to mcp.Transport. Writes are
+// serialized because gorilla/websocket only supports one concurrent writer
+// and responses, keepalive pings and log notifications all come from
+// different goroutines.
+type webSocketTransport struct {
+	conn *websocket.Conn
+
+	writeMu sync.Mutex
+}
+
+func newWebSocketTransport(conn *websocket.Conn) *webSocketTransport {
+	return &webSocketTransport{conn: conn}
+}
+
+func (t *webSocketTransport) ReadMessage() ([]byte, error) {
+	_, data, err := t.conn.ReadMessage()
+	return data, err
+}
+
+func (t *webSocketTransport) WriteMessage(data []byte) error {
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	return t.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func (t *webSocketTransport) Close() error {
+	return t.conn.Close()
+}
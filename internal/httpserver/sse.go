@@ -0,0 +1,301 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package httpserver
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/microsoft/playwright-mcp/internal/mcp"
+)
+
+// sseReplayBufferSize is how many outgoing events are retained per session
+// for Last-Event-ID resumption.
+const sseReplayBufferSize = 1000
+
+// sseEvent is one outgoing message with its SSE event id.
+type sseEvent struct {
+	id   int
+	data []byte
+}
+
+// sseSession is an mcp.Transport over Server-Sent Events. Client-to-server
+// messages arrive via POST, server-to-client messages are written to the
+// current event stream. Every outgoing message gets a monotonically
+// increasing event id and is kept in a bounded replay buffer so a client
+// reconnecting with Last-Event-ID does not lose in-flight tool results.
+type sseSession struct {
+	id       string
+	incoming chan []byte
+
+	mu         sync.Mutex
+	events     []sseEvent
+	nextID     int
+	writer     http.ResponseWriter
+	flusher    http.Flusher
+	writerGone chan struct{}
+	closed     bool
+	// detachedSince is when the event stream dropped; zero while a stream
+	// is attached. The reaper uses it to collect sessions whose client
+	// never came back.
+	detachedSince time.Time
+}
+
+func newSSESession() *sseSession {
+	return &sseSession{
+		id:            randomHex(16),
+		incoming:      make(chan []byte, 64),
+		detachedSince: time.Now(),
+	}
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func (s *sseSession) ReadMessage() ([]byte, error) {
+	data, ok := <-s.incoming
+	if !ok {
+		return nil, io.EOF
+	}
+	return data, nil
+}
+
+func (s *sseSession) WriteMessage(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return io.ErrClosedPipe
+	}
+	s.nextID++
+	event := sseEvent{id: s.nextID, data: data}
+	s.events = append(s.events, event)
+	if len(s.events) > sseReplayBufferSize {
+		s.events = s.events[len(s.events)-sseReplayBufferSize:]
+	}
+	if s.writer != nil {
+		s.writeEventLocked(event)
+	}
+	return nil
+}
+
+func (s *sseSession) writeEventLocked(event sseEvent) {
+	fmt.Fprintf(s.writer, "id: %d\ndata: %s\n\n", event.id, event.data)
+	s.flusher.Flush()
+}
+
+// attach binds the session to a new event stream, replaying buffered events
+// newer than lastEventID. It returns a channel that is closed when the
+// stream is detached again.
+func (s *sseSession) attach(w http.ResponseWriter, flusher http.Flusher, lastEventID int) chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.writerGone != nil {
+		close(s.writerGone)
+	}
+	s.writer = w
+	s.flusher = flusher
+	s.writerGone = make(chan struct{})
+	s.detachedSince = time.Time{}
+	for _, event := range s.events {
+		if event.id > lastEventID {
+			s.writeEventLocked(event)
+		}
+	}
+	return s.writerGone
+}
+
+// detach disconnects the current event stream; the session stays resumable.
+func (s *sseSession) detach(w http.ResponseWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.writer != w {
+		return
+	}
+	s.writer = nil
+	s.flusher = nil
+	s.detachedSince = time.Now()
+	if s.writerGone != nil {
+		close(s.writerGone)
+		s.writerGone = nil
+	}
+}
+
+// Detached reports whether the session currently has no event stream. The
+// MCP keepalive checks it so pings the client cannot possibly answer do not
+// kill a session that is still inside its resume window.
+func (s *sseSession) Detached() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.detachedSince.IsZero()
+}
+
+// detachedFor reports how long the session has been without a stream, or 0
+// while one is attached.
+func (s *sseSession) detachedFor() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.detachedSince.IsZero() {
+		return 0
+	}
+	return time.Since(s.detachedSince)
+}
+
+func (s *sseSession) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	close(s.incoming)
+	if s.writerGone != nil {
+		close(s.writerGone)
+		s.writerGone = nil
+	}
+	s.writer = nil
+	s.flusher = nil
+	return nil
+}
+
+// handleSSE serves the SSE transport: GET opens (or resumes) the event
+// stream, POST delivers client messages to the session.
+func (s *Server) handleSSE(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.handleSSEStream(w, r)
+	case http.MethodPost:
+		s.handleSSEPost(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleSSEStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	sessionID := r.URL.Query().Get("sessionId")
+	lastEventID := 0
+	if value := r.Header.Get("Last-Event-ID"); value != "" {
+		if id, err := strconv.Atoi(value); err == nil {
+			lastEventID = id
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	var session *sseSession
+	if sessionID != "" {
+		session = s.lookupSSESession(sessionID)
+		if session == nil {
+			http.Error(w, "unknown session", http.StatusNotFound)
+			return
+		}
+	} else {
+		overrides, err := sessionOverridesFromQuery(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		backend, err := s.server.NewConnection(overrides)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		session = newSSESession()
+		s.sseMu.Lock()
+		s.sseSessions[session.id] = session
+		s.sseMu.Unlock()
+		conn := mcp.NewConnection(session, backend, mcp.Implementation{Name: "Playwright"})
+		conn.StartKeepalive(keepaliveInterval)
+		go func() {
+			conn.Run()
+			s.sseMu.Lock()
+			delete(s.sseSessions, session.id)
+			s.sseMu.Unlock()
+		}()
+		// The endpoint event tells the client where to POST its messages.
+		fmt.Fprintf(w, "event: endpoint\ndata: %s/sse?sessionId=%s\n\n", s.pathPrefix, session.id)
+		flusher.Flush()
+	}
+
+	gone := session.attach(w, flusher, lastEventID)
+	select {
+	case <-r.Context().Done():
+		session.detach(w)
+	case <-gone:
+	}
+}
+
+func (s *Server) handleSSEPost(w http.ResponseWriter, r *http.Request) {
+	session := s.lookupSSESession(r.URL.Query().Get("sessionId"))
+	if session == nil {
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	select {
+	case session.incoming <- data:
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		http.Error(w, "session backlog full", http.StatusServiceUnavailable)
+	}
+}
+
+// sseDetachTimeout is how long an SSE session may sit without a connected
+// event stream before the reaper closes it and the browser context behind
+// it. Long enough for proxy blips, short enough that abandoned clients
+// don't pile up headless browsers.
+const sseDetachTimeout = 2 * time.Minute
+
+// startSSEReaper periodically closes sessions whose client disconnected and
+// never resumed. Closing the session unblocks the MCP message loop, which
+// closes the backend and its browser context.
+func (s *Server) startSSEReaper() {
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			s.sseMu.Lock()
+			var stale []*sseSession
+			for _, session := range s.sseSessions {
+				if session.detachedFor() > sseDetachTimeout {
+					stale = append(stale, session)
+				}
+			}
+			s.sseMu.Unlock()
+			for _, session := range stale {
+				s.log.Info().Str("sessionId", session.id).Msg("reaping disconnected SSE session")
+				session.Close()
+			}
+		}
+	}()
+}
+
+func (s *Server) lookupSSESession(id string) *sseSession {
+	if id == "" {
+		return nil
+	}
+	s.sseMu.Lock()
+	defer s.sseMu.Unlock()
+	return s.sseSessions[id]
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package httpserver
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSSESessionReplaysAfterLastEventID(t *testing.T) {
+	session := newSSESession()
+	for i := 1; i <= 3; i++ {
+		if err := session.WriteMessage([]byte(fmt.Sprintf("m%d", i))); err != nil {
+			t.Fatalf("WriteMessage: %v", err)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	session.attach(recorder, recorder, 1)
+	body := recorder.Body.String()
+	if strings.Contains(body, "id: 1\n") {
+		t.Errorf("attach replayed event 1, which the client already has:\n%s", body)
+	}
+	for _, want := range []string{"id: 2\ndata: m2\n\n", "id: 3\ndata: m3\n\n"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("attach did not replay %q:\n%s", want, body)
+		}
+	}
+
+	// Messages written while attached go straight to the stream.
+	if err := session.WriteMessage([]byte("m4")); err != nil {
+		t.Fatalf("WriteMessage: %v", err)
+	}
+	if !strings.Contains(recorder.Body.String(), "id: 4\ndata: m4\n\n") {
+		t.Error("live write did not reach the attached stream")
+	}
+}
+
+func TestSSESessionReplayBufferIsBounded(t *testing.T) {
+	session := newSSESession()
+	total := sseReplayBufferSize + 5
+	for i := 1; i <= total; i++ {
+		if err := session.WriteMessage([]byte("m")); err != nil {
+			t.Fatalf("WriteMessage: %v", err)
+		}
+	}
+	recorder := httptest.NewRecorder()
+	session.attach(recorder, recorder, 0)
+	body := recorder.Body.String()
+	if strings.Contains(body, "id: 5\n") {
+		t.Error("events beyond the replay buffer should have been dropped")
+	}
+	if !strings.Contains(body, fmt.Sprintf("id: %d\n", total)) {
+		t.Error("the newest event is missing from the replay")
+	}
+}
+
+func TestSSESessionDetached(t *testing.T) {
+	session := newSSESession()
+	if !session.Detached() {
+		t.Error("a fresh session has no stream and should report detached")
+	}
+	recorder := httptest.NewRecorder()
+	gone := session.attach(recorder, recorder, 0)
+	if session.Detached() {
+		t.Error("session should not report detached while a stream is attached")
+	}
+	session.detach(recorder)
+	if !session.Detached() {
+		t.Error("session should report detached after the stream drops")
+	}
+	select {
+	case <-gone:
+	default:
+		t.Error("detach should close the writerGone channel")
+	}
+	if session.detachedFor() <= 0 {
+		t.Error("detachedFor should grow once the stream is gone")
+	}
+}
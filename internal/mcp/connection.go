@@ -0,0 +1,295 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// maxCompletionValues caps completion/complete responses per the MCP spec.
+const maxCompletionValues = 100
+
+// Completer is optionally implemented by backends that can suggest argument
+// values for completion/complete.
+type Completer interface {
+	Complete(ref CompleteRef, argument, value string) []string
+}
+
+// ServerBackend is implemented by the Playwright server and plugged into a
+// Connection per client.
+type ServerBackend interface {
+	// Initialize is called once when the client sends "initialize". The raw
+	// params are passed along so backends can pick up protocol extensions
+	// (e.g. per-session config overrides).
+	Initialize(clientInfo Implementation, rawParams json.RawMessage) error
+	// ListTools returns the tools visible to this connection.
+	ListTools() []Tool
+	// CallTool dispatches a single tool invocation.
+	CallTool(name string, arguments json.RawMessage) (*CallToolResult, error)
+	// Close releases all resources held on behalf of this connection.
+	Close() error
+}
+
+// Connection pumps JSON-RPC messages between one transport and a backend.
+type Connection struct {
+	transport  Transport
+	backend    ServerBackend
+	serverInfo Implementation
+
+	logMu    sync.Mutex
+	logLevel string
+
+	// sendMu serializes writes to the transport: batch members, keepalive
+	// pings and notifications are all sent from their own goroutines.
+	sendMu sync.Mutex
+
+	pendingMu sync.Mutex
+	pending   map[string]chan *Response
+	nextID    int
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewConnection binds a transport to a backend. Call Run to start the message
+// loop. Backends that implement Bind(*Connection) receive the connection so
+// they can send notifications.
+func NewConnection(transport Transport, backend ServerBackend, serverInfo Implementation) *Connection {
+	c := &Connection{
+		transport:  transport,
+		backend:    backend,
+		serverInfo: serverInfo,
+		logLevel:   "info",
+		pending:    make(map[string]chan *Response),
+		done:       make(chan struct{}),
+	}
+	if binder, ok := backend.(interface{ Bind(*Connection) }); ok {
+		binder.Bind(c)
+	}
+	return c
+}
+
+// Run reads messages until the transport closes, dispatching each one. It
+// always closes the backend before returning.
+func (c *Connection) Run() error {
+	defer c.backend.Close()
+	for {
+		data, err := c.transport.ReadMessage()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		c.handleMessages(data)
+	}
+}
+
+// handleMessages parses one incoming payload and dispatches it. The payload
+// is either a single JSON-RPC message or a batch array of them.
+func (c *Connection) handleMessages(data []byte) {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		c.handleBatch(trimmed)
+		return
+	}
+	if resp := c.handleMessage(data); resp != nil {
+		c.send(resp)
+	}
+}
+
+// handleMessage processes one message and returns its response, or nil for
+// notifications and responses to server-initiated requests.
+func (c *Connection) handleMessage(data []byte) *Response {
+	var req Request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return &Response{JSONRPC: "2.0", Error: &ResponseError{
+			Code:    ErrorCodeParseError,
+			Message: fmt.Sprintf("failed to parse message: %v", err),
+		}}
+	}
+	// A message without a method is a response to a server-initiated request
+	// (e.g. a keepalive ping).
+	if req.Method == "" && req.ID != nil {
+		var resp Response
+		if err := json.Unmarshal(data, &resp); err == nil {
+			c.handleResponse(&resp)
+		}
+		return nil
+	}
+	return c.handleRequest(&req)
+}
+
+// handleBatch dispatches the members of a batch concurrently and sends one
+// response array whose entries are ordered like the incoming batch, with
+// notification slots elided per the JSON-RPC spec.
+func (c *Connection) handleBatch(data []byte) {
+	var members []json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		c.sendError(nil, ErrorCodeParseError, fmt.Sprintf("failed to parse batch: %v", err))
+		return
+	}
+	if len(members) == 0 {
+		c.sendError(nil, ErrorCodeInvalidRequest, "empty batch")
+		return
+	}
+
+	responses := make([]*Response, len(members))
+	var wg sync.WaitGroup
+	for i, member := range members {
+		wg.Add(1)
+		go func(i int, member json.RawMessage) {
+			defer wg.Done()
+			responses[i] = c.handleMessage(member)
+		}(i, member)
+	}
+	wg.Wait()
+
+	batch := make([]*Response, 0, len(responses))
+	for _, resp := range responses {
+		if resp != nil {
+			batch = append(batch, resp)
+		}
+	}
+	if len(batch) > 0 {
+		c.send(batch)
+	}
+}
+
+func (c *Connection) handleResponse(resp *Response) {
+	c.pendingMu.Lock()
+	ch, ok := c.pending[string(*resp.ID)]
+	if ok {
+		delete(c.pending, string(*resp.ID))
+	}
+	c.pendingMu.Unlock()
+	if ok {
+		ch <- resp
+	}
+}
+
+func (c *Connection) handleRequest(req *Request) *Response {
+	result, err := c.dispatch(req)
+	if req.ID == nil {
+		// Notifications get no response.
+		return nil
+	}
+	if err != nil {
+		var respErr *ResponseError
+		if !errors.As(err, &respErr) {
+			respErr = &ResponseError{Code: ErrorCodeInternalError, Message: err.Error()}
+		}
+		return &Response{JSONRPC: "2.0", ID: req.ID, Error: respErr}
+	}
+	return &Response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func (c *Connection) dispatch(req *Request) (any, error) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ClientInfo Implementation `json:"clientInfo"`
+		}
+		if len(req.Params) > 0 {
+			if err := json.Unmarshal(req.Params, &params); err != nil {
+				return nil, &ResponseError{Code: ErrorCodeInvalidParams, Message: err.Error()}
+			}
+		}
+		if err := c.backend.Initialize(params.ClientInfo, req.Params); err != nil {
+			return nil, err
+		}
+		capabilities := ServerCapabilities{
+			Tools:   &ToolsCapability{ListChanged: true},
+			Logging: &LoggingCapability{},
+		}
+		if _, ok := c.backend.(Completer); ok {
+			capabilities.Completions = &CompletionsCapability{}
+		}
+		return &InitializeResult{
+			ProtocolVersion: LatestProtocolVersion,
+			Capabilities:    capabilities,
+			ServerInfo:      c.serverInfo,
+		}, nil
+	case "notifications/initialized":
+		return nil, nil
+	case "ping":
+		return struct{}{}, nil
+	case "completion/complete":
+		completer, ok := c.backend.(Completer)
+		if !ok {
+			return nil, &ResponseError{Code: ErrorCodeMethodNotFound, Message: "completions not supported"}
+		}
+		var params CompleteParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &ResponseError{Code: ErrorCodeInvalidParams, Message: err.Error()}
+		}
+		values := completer.Complete(params.Ref, params.Argument.Name, params.Argument.Value)
+		total := len(values)
+		hasMore := false
+		if len(values) > maxCompletionValues {
+			values = values[:maxCompletionValues]
+			hasMore = true
+		}
+		return &CompleteResult{Completion: Completion{Values: values, Total: total, HasMore: hasMore}}, nil
+	case "logging/setLevel":
+		var params struct {
+			Level string `json:"level"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &ResponseError{Code: ErrorCodeInvalidParams, Message: err.Error()}
+		}
+		if err := c.SetLogLevel(params.Level); err != nil {
+			return nil, err
+		}
+		return struct{}{}, nil
+	case "tools/list":
+		return &ListToolsResult{Tools: c.backend.ListTools()}, nil
+	case "tools/call":
+		var params CallToolParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &ResponseError{Code: ErrorCodeInvalidParams, Message: err.Error()}
+		}
+		result, err := c.backend.CallTool(params.Name, params.Arguments)
+		if err != nil {
+			// Tool failures are reported in-band so the model can react.
+			return &CallToolResult{
+				Content: []any{NewTextContent(err.Error())},
+				IsError: true,
+			}, nil
+		}
+		return result, nil
+	default:
+		return nil, &ResponseError{Code: ErrorCodeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+	}
+}
+
+// Notify sends a server-initiated notification to the client.
+func (c *Connection) Notify(method string, params any) error {
+	return c.send(&Notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (c *Connection) send(msg any) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	return c.transport.WriteMessage(data)
+}
+
+func (c *Connection) sendError(id *json.RawMessage, code int, message string) {
+	c.send(&Response{JSONRPC: "2.0", ID: id, Error: &ResponseError{Code: code, Message: message}})
+}
+
+// Close shuts the transport down, unblocking Run.
+func (c *Connection) Close() error {
+	c.closeOnce.Do(func() { close(c.done) })
+	return c.transport.Close()
+}
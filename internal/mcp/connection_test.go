@@ -0,0 +1,118 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package mcp
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+)
+
+// testTransport feeds canned messages to a Connection and collects what it
+// writes back.
+type testTransport struct {
+	incoming chan []byte
+	outgoing chan []byte
+}
+
+func newTestTransport() *testTransport {
+	return &testTransport{
+		incoming: make(chan []byte),
+		outgoing: make(chan []byte, 16),
+	}
+}
+
+func (t *testTransport) ReadMessage() ([]byte, error) {
+	data, ok := <-t.incoming
+	if !ok {
+		return nil, io.EOF
+	}
+	return data, nil
+}
+
+func (t *testTransport) WriteMessage(data []byte) error {
+	t.outgoing <- data
+	return nil
+}
+
+func (t *testTransport) Close() error { return nil }
+
+// testBackend answers every tool call with the tool's name.
+type testBackend struct{}
+
+func (testBackend) Initialize(Implementation, json.RawMessage) error { return nil }
+func (testBackend) ListTools() []Tool                                { return nil }
+func (testBackend) Close() error                                     { return nil }
+
+func (testBackend) CallTool(name string, _ json.RawMessage) (*CallToolResult, error) {
+	return &CallToolResult{Content: []any{NewTextContent(name)}}, nil
+}
+
+func TestBatchResponsesKeepRequestOrder(t *testing.T) {
+	transport := newTestTransport()
+	conn := NewConnection(transport, testBackend{}, Implementation{Name: "test"})
+	done := make(chan struct{})
+	go func() {
+		conn.Run()
+		close(done)
+	}()
+
+	transport.incoming <- []byte(`[
+		{"jsonrpc":"2.0","id":1,"method":"ping"},
+		{"jsonrpc":"2.0","method":"notifications/initialized"},
+		{"jsonrpc":"2.0","id":2,"method":"ping"},
+		{"jsonrpc":"2.0","id":3,"method":"ping"}
+	]`)
+
+	var data []byte
+	select {
+	case data = <-transport.outgoing:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the batch response")
+	}
+	var responses []Response
+	if err := json.Unmarshal(data, &responses); err != nil {
+		t.Fatalf("batch response is not a JSON array: %v\n%s", err, data)
+	}
+	// The notification slot is elided; the three request responses come back
+	// in the order their requests appeared.
+	if len(responses) != 3 {
+		t.Fatalf("got %d responses, want 3:\n%s", len(responses), data)
+	}
+	for i, want := range []string{"1", "2", "3"} {
+		if responses[i].ID == nil || string(*responses[i].ID) != want {
+			t.Errorf("response %d has id %s, want %s", i, *responses[i].ID, want)
+		}
+	}
+
+	close(transport.incoming)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the transport closed")
+	}
+}
+
+func TestEmptyBatchIsRejected(t *testing.T) {
+	transport := newTestTransport()
+	conn := NewConnection(transport, testBackend{}, Implementation{Name: "test"})
+	go conn.Run()
+	defer close(transport.incoming)
+
+	transport.incoming <- []byte(`[]`)
+	var data []byte
+	select {
+	case data = <-transport.outgoing:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the error response")
+	}
+	var response Response
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatalf("error response did not parse: %v\n%s", err, data)
+	}
+	if response.Error == nil || response.Error.Code != ErrorCodeInvalidRequest {
+		t.Errorf("empty batch should produce an invalid-request error, got %+v", response)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SendRequest sends a server-initiated request and waits for the matching
+// response or the timeout, whichever comes first.
+func (c *Connection) SendRequest(method string, params any, timeout time.Duration) (*Response, error) {
+	c.pendingMu.Lock()
+	c.nextID++
+	id := json.RawMessage(fmt.Sprintf("\"s%d\"", c.nextID))
+	ch := make(chan *Response, 1)
+	c.pending[string(id)] = ch
+	c.pendingMu.Unlock()
+
+	cleanup := func() {
+		c.pendingMu.Lock()
+		delete(c.pending, string(id))
+		c.pendingMu.Unlock()
+	}
+
+	if err := c.send(&Request{JSONRPC: "2.0", ID: &id, Method: method, Params: marshalParams(params)}); err != nil {
+		cleanup()
+		return nil, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-timer.C:
+		cleanup()
+		return nil, fmt.Errorf("timed out waiting for response to %s", method)
+	case <-c.done:
+		cleanup()
+		return nil, fmt.Errorf("connection closed")
+	}
+}
+
+func marshalParams(params any) json.RawMessage {
+	if params == nil {
+		return nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// detachable is implemented by transports whose client may drop and resume
+// later (the SSE transport). While no client is attached a ping cannot be
+// answered, so the keepalive skips it and leaves the session's fate to the
+// transport's own resume timeout.
+type detachable interface {
+	Detached() bool
+}
+
+// StartKeepalive periodically pings the client. When a ping goes unanswered
+// the connection is closed, which unblocks Run and releases the backend
+// (including its browser context). Used on network transports where TCP
+// alone does not surface half-open connections.
+func (c *Connection) StartKeepalive(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.done:
+				return
+			case <-ticker.C:
+				if transport, ok := c.transport.(detachable); ok && transport.Detached() {
+					continue
+				}
+				if _, err := c.SendRequest("ping", struct{}{}, interval); err != nil {
+					c.Close()
+					return
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,59 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package mcp
+
+import "fmt"
+
+// Log levels in increasing severity, as defined by RFC 5424 and adopted by
+// the MCP logging capability.
+var logLevels = []string{
+	"debug",
+	"info",
+	"notice",
+	"warning",
+	"error",
+	"critical",
+	"alert",
+	"emergency",
+}
+
+// logLevelSeverity returns the rank of a level, or -1 if unknown.
+func logLevelSeverity(level string) int {
+	for i, l := range logLevels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetLogLevel sets the minimum severity of messages delivered to the client.
+func (c *Connection) SetLogLevel(level string) error {
+	if logLevelSeverity(level) < 0 {
+		return &ResponseError{
+			Code:    ErrorCodeInvalidParams,
+			Message: fmt.Sprintf("unknown log level: %q", level),
+		}
+	}
+	c.logMu.Lock()
+	c.logLevel = level
+	c.logMu.Unlock()
+	return nil
+}
+
+// Log sends a "notifications/message" entry to the client if it passes the
+// session's log level.
+func (c *Connection) Log(level, logger string, data any) {
+	c.logMu.Lock()
+	minimum := c.logLevel
+	c.logMu.Unlock()
+	if logLevelSeverity(level) < logLevelSeverity(minimum) {
+		return
+	}
+	c.Notify("notifications/message", &LoggingMessageParams{
+		Level:  level,
+		Logger: logger,
+		Data:   data,
+	})
+}
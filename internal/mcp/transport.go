@@ -0,0 +1,67 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package mcp
+
+import (
+	"bufio"
+	"io"
+	"sync"
+)
+
+// Transport moves raw JSON-RPC payloads between the server and one client.
+// Implementations exist for stdio, SSE/streamable HTTP and WebSocket.
+type Transport interface {
+	// ReadMessage blocks until the next payload arrives or the transport
+	// closes, in which case it returns io.EOF.
+	ReadMessage() ([]byte, error)
+	// WriteMessage delivers one payload to the client.
+	WriteMessage(data []byte) error
+	// Close tears the transport down. Subsequent reads return io.EOF.
+	Close() error
+}
+
+// StdioTransport speaks newline-delimited JSON-RPC over a reader/writer pair,
+// typically os.Stdin/os.Stdout.
+type StdioTransport struct {
+	reader *bufio.Reader
+	writer io.Writer
+	closer io.Closer
+
+	writeMu sync.Mutex
+}
+
+// NewStdioTransport wraps the given streams into a Transport. closer may be
+// nil when the streams do not need explicit shutdown.
+func NewStdioTransport(r io.Reader, w io.Writer, closer io.Closer) *StdioTransport {
+	return &StdioTransport{
+		reader: bufio.NewReader(r),
+		writer: w,
+		closer: closer,
+	}
+}
+
+func (t *StdioTransport) ReadMessage() ([]byte, error) {
+	line, err := t.reader.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+func (t *StdioTransport) WriteMessage(data []byte) error {
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.writer.Write(data); err != nil {
+		return err
+	}
+	_, err := t.writer.Write([]byte{'\n'})
+	return err
+}
+
+func (t *StdioTransport) Close() error {
+	if t.closer != nil {
+		return t.closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package mcp
+
+import "encoding/json"
+
+// LatestProtocolVersion is the MCP protocol revision this server implements.
+const LatestProtocolVersion = "2025-06-18"
+
+// JSON-RPC 2.0 error codes.
+const (
+	ErrorCodeParseError     = -32700
+	ErrorCodeInvalidRequest = -32600
+	ErrorCodeMethodNotFound = -32601
+	ErrorCodeInvalidParams  = -32602
+	ErrorCodeInternalError  = -32603
+)
+
+// Request is a single incoming JSON-RPC message. Notifications carry a nil ID.
+type Request struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+}
+
+// Response is a single outgoing JSON-RPC message.
+type Response struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Result  any              `json:"result,omitempty"`
+	Error   *ResponseError   `json:"error,omitempty"`
+}
+
+// Notification is a server-initiated JSON-RPC message without an ID.
+type Notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+// ResponseError is the error member of a JSON-RPC response.
+type ResponseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (e *ResponseError) Error() string { return e.Message }
+
+// Implementation identifies a client or server in the initialize handshake.
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ServerCapabilities advertises which optional protocol features this server
+// supports.
+type ServerCapabilities struct {
+	Tools       *ToolsCapability       `json:"tools,omitempty"`
+	Logging     *LoggingCapability     `json:"logging,omitempty"`
+	Completions *CompletionsCapability `json:"completions,omitempty"`
+}
+
+// ToolsCapability is the "tools" member of ServerCapabilities.
+type ToolsCapability struct {
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// LoggingCapability is the "logging" member of ServerCapabilities.
+type LoggingCapability struct{}
+
+// CompletionsCapability is the "completions" member of ServerCapabilities.
+type CompletionsCapability struct{}
+
+// CompleteRef identifies what a completion request is about.
+type CompleteRef struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// CompleteArgument is the argument being completed.
+type CompleteArgument struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CompleteParams is the request payload for "completion/complete".
+type CompleteParams struct {
+	Ref      CompleteRef      `json:"ref"`
+	Argument CompleteArgument `json:"argument"`
+}
+
+// Completion is the inner result of "completion/complete".
+type Completion struct {
+	Values  []string `json:"values"`
+	Total   int      `json:"total,omitempty"`
+	HasMore bool     `json:"hasMore,omitempty"`
+}
+
+// CompleteResult is the response payload for "completion/complete".
+type CompleteResult struct {
+	Completion Completion `json:"completion"`
+}
+
+// LoggingMessageParams is the payload of a "notifications/message"
+// notification.
+type LoggingMessageParams struct {
+	Level  string `json:"level"`
+	Logger string `json:"logger,omitempty"`
+	Data   any    `json:"data"`
+}
+
+// InitializeResult is the response payload for the "initialize" request.
+type InitializeResult struct {
+	ProtocolVersion string             `json:"protocolVersion"`
+	Capabilities    ServerCapabilities `json:"capabilities"`
+	ServerInfo      Implementation     `json:"serverInfo"`
+}
+
+// ToolAnnotations carries the behavior hints for a tool as defined by the MCP
+// specification. Hints are advisory: clients use them to decide which calls
+// need user confirmation.
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    bool   `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	IdempotentHint  bool   `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
+}
+
+// Tool is a single entry of the "tools/list" response.
+type Tool struct {
+	Name        string           `json:"name"`
+	Title       string           `json:"title,omitempty"`
+	Description string           `json:"description,omitempty"`
+	InputSchema map[string]any   `json:"inputSchema"`
+	Annotations *ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// ListToolsResult is the response payload for "tools/list".
+type ListToolsResult struct {
+	Tools []Tool `json:"tools"`
+}
+
+// CallToolParams is the request payload for "tools/call".
+type CallToolParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// TextContent is a text block of a tool result.
+type TextContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// ImageContent is an inline image block of a tool result.
+type ImageContent struct {
+	Type     string `json:"type"`
+	Data     string `json:"data"`
+	MimeType string `json:"mimeType"`
+}
+
+// CallToolResult is the response payload for "tools/call".
+type CallToolResult struct {
+	Content []any `json:"content"`
+	IsError bool  `json:"isError,omitempty"`
+}
+
+// NewTextContent returns a TextContent with the type discriminator set.
+func NewTextContent(text string) TextContent {
+	return TextContent{Type: "text", Text: text}
+}
+
+// NewImageContent returns an ImageContent with the type discriminator set.
+func NewImageContent(data, mimeType string) ImageContent {
+	return ImageContent{Type: "image", Data: data, MimeType: mimeType}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+// Package secrets keeps credential values out of the LLM transcript. Tools
+// accept secret references by name; the real values are substituted on the
+// browser side and masked everywhere output flows back to the client.
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Store holds named secret values loaded from a secrets file.
+type Store struct {
+	values map[string]string
+}
+
+// LoadFile parses a file of name=value pairs, one per line. Blank lines and
+// lines starting with # are ignored; values keep embedded = signs.
+func LoadFile(path string) (*Store, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+	values := make(map[string]string)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("secrets file line %d: expected name=value", i+1)
+		}
+		values[name] = strings.TrimSpace(value)
+	}
+	return &Store{values: values}, nil
+}
+
+// Lookup resolves a secret reference by name.
+func (s *Store) Lookup(name string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	value, ok := s.values[name]
+	return value, ok
+}
+
+// Names lists the available secret names (never the values), sorted, so
+// tools can tell the agent what it may reference.
+func (s *Store) Names() []string {
+	if s == nil {
+		return nil
+	}
+	names := make([]string, 0, len(s.values))
+	for name := range s.values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Redact masks every occurrence of every secret value in text with
+// <secret:NAME>. Longer values are replaced first so a secret that is a
+// substring of another cannot leak the remainder.
+func (s *Store) Redact(text string) string {
+	if s == nil || len(s.values) == 0 || text == "" {
+		return text
+	}
+	type pair struct{ name, value string }
+	pairs := make([]pair, 0, len(s.values))
+	for name, value := range s.values {
+		if value != "" {
+			pairs = append(pairs, pair{name, value})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return len(pairs[i].value) > len(pairs[j].value) })
+	for _, p := range pairs {
+		text = strings.ReplaceAll(text, p.value, "<secret:"+p.name+">")
+	}
+	return text
+}
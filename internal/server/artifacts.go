@@ -0,0 +1,36 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package server
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// SetArtifactBaseURL records where the output directory is served over HTTP.
+// Empty (the stdio default) means artifacts are only reachable by local
+// path.
+func (s *Server) SetArtifactBaseURL(baseURL string) {
+	s.mu.Lock()
+	s.artifactBaseURL = baseURL
+	s.mu.Unlock()
+}
+
+// ArtifactURL returns the HTTP URL for a file inside the output directory,
+// or "" when the file is not under it or no HTTP server is running. Tool
+// results include this next to the local path so remote clients can fetch
+// the artifact.
+func (s *Server) ArtifactURL(localPath string) string {
+	s.mu.Lock()
+	baseURL := s.artifactBaseURL
+	s.mu.Unlock()
+	if baseURL == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(s.config.OutputDir, localPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return baseURL + "/" + filepath.ToSlash(rel)
+}
@@ -0,0 +1,45 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package server
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/microsoft/playwright-mcp/internal/config"
+	"github.com/microsoft/playwright-mcp/internal/mcp"
+)
+
+// Complete implements mcp.Completer: it suggests values for tool arguments
+// such as device names, capability names and live tab indices.
+func (c *Connection) Complete(ref mcp.CompleteRef, argument, value string) []string {
+	if ref.Type != "ref/tool" {
+		return nil
+	}
+	var candidates []string
+	switch {
+	case ref.Name == "browser_tab_select" && argument == "index":
+		for i := range c.ctx.Tabs() {
+			candidates = append(candidates, strconv.Itoa(i))
+		}
+	case argument == "device":
+		candidates = config.DeviceNames()
+	case argument == "capability" || argument == "capabilities":
+		candidates = config.KnownCapabilities
+	}
+	return filterByPrefix(candidates, value)
+}
+
+func filterByPrefix(candidates []string, prefix string) []string {
+	if prefix == "" {
+		return candidates
+	}
+	var matched []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(prefix)) {
+			matched = append(matched, candidate)
+		}
+	}
+	return matched
+}
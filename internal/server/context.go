@@ -0,0 +1,373 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+
+	"github.com/microsoft/playwright-mcp/internal/browser"
+	"github.com/microsoft/playwright-mcp/internal/config"
+	"github.com/microsoft/playwright-mcp/internal/secrets"
+	"github.com/microsoft/playwright-mcp/internal/tools"
+)
+
+// browserContext is the tools.Context implementation. The browser launches
+// lazily: creating the context (and with it the factory) is free, and the
+// Playwright context only comes up when the first tool actually needs a
+// page. Clients that connect just to list tools never pay browser startup.
+type browserContext struct {
+	config        *config.FullConfig
+	secrets       *secrets.Store
+	factory       browser.ContextFactory
+	downloadsList *downloadTracker
+	// ownsFactory is false when the factory is shared with other sessions
+	// (the server's pooled factory); only owned factories are closed with
+	// the context.
+	ownsFactory bool
+
+	mu        sync.Mutex
+	pwContext playwright.BrowserContext
+	tabs      []*browser.Tab
+	// currentTab is the tab tool calls act on; nil only while no tabs are
+	// open.
+	currentTab *browser.Tab
+	// popupNotes queues one line per popup adopted since the last tool
+	// response, so the agent learns about OAuth windows and target=_blank
+	// tabs it did not open itself.
+	popupNotes []string
+}
+
+func newBrowserContext(cfg *config.FullConfig, store *secrets.Store) *browserContext {
+	return newBrowserContextWithFactory(cfg, store, browser.NewContextFactory(cfg), true)
+}
+
+// newBrowserContextWithFactory uses a caller-supplied factory instead of
+// deriving one from the config: sessions attached to a managed browser
+// instance pass the instance's CDP factory here, and sessions on the base
+// config pass the server's shared factory (ownsFactory false) so its warm
+// context pool outlives any one session.
+func newBrowserContextWithFactory(cfg *config.FullConfig, store *secrets.Store, factory browser.ContextFactory, ownsFactory bool) *browserContext {
+	return &browserContext{
+		config:        cfg,
+		secrets:       store,
+		factory:       factory,
+		downloadsList: newDownloadTracker(cfg.DownloadsDir()),
+		ownsFactory:   ownsFactory,
+	}
+}
+
+// ensureContext launches the browser on first use. Pages the context
+// already has (a CDP-attached browser brings its own) are adopted as tabs,
+// and every page opened later — window.open, target=_blank, NewPage — is
+// auto-tracked via the context's page event.
+func (c *browserContext) ensureContext() (playwright.BrowserContext, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pwContext != nil {
+		return c.pwContext, nil
+	}
+	context, err := c.factory.CreateContext()
+	if err != nil {
+		return nil, err
+	}
+	if c.config.SaveTrace {
+		err := context.Tracing().Start(playwright.TracingStartOptions{
+			Screenshots: playwright.Bool(true),
+			Snapshots:   playwright.Bool(true),
+		})
+		if err != nil {
+			context.Close()
+			return nil, fmt.Errorf("failed to start trace recording: %w", err)
+		}
+	}
+	c.pwContext = context
+	context.OnPage(func(page playwright.Page) {
+		c.adoptPage(page)
+	})
+	for _, page := range context.Pages() {
+		c.adoptPageLocked(page)
+	}
+	return context, nil
+}
+
+// adoptPage wraps a page as a Tab unless it is tracked already, and makes
+// it current when no tab is.
+func (c *browserContext) adoptPage(page playwright.Page) *browser.Tab {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.adoptPageLocked(page)
+}
+
+func (c *browserContext) adoptPageLocked(page playwright.Page) *browser.Tab {
+	for _, tab := range c.tabs {
+		if tab.Page() == page {
+			return tab
+		}
+	}
+	tab := browser.NewTab(page, browser.TabHooks{
+		Popup: func(popup playwright.Page) {
+			adopted := c.adoptPage(popup)
+			c.mu.Lock()
+			index := indexOfTab(c.tabs, adopted)
+			c.popupNotes = append(c.popupNotes, fmt.Sprintf(
+				"New tab opened by the page: %s (select it with browser_tab_select index %d)", popup.URL(), index))
+			c.mu.Unlock()
+		},
+		Closed: func(closed *browser.Tab) {
+			c.removeTab(closed)
+		},
+	})
+	tab.SetSnapshotFilter(browser.SnapshotFilter{
+		Roles:     c.config.Snapshot.ExcludeRoles,
+		Selectors: c.config.Snapshot.ExcludeSelectors,
+		Areas:     c.config.Snapshot.ExcludeAreas,
+	})
+	tab.SetDialogPolicy(dialogPolicy(c.config.Dialogs))
+	c.tabs = append(c.tabs, tab)
+	if c.currentTab == nil {
+		c.currentTab = tab
+	}
+	return tab
+}
+
+// dialogPolicy translates the dialog configuration into the tab-level
+// policy: "auto-accept"/"auto-dismiss" become default actions, "manual"
+// (and unset) route dialogs to the model.
+func dialogPolicy(cfg config.DialogsConfig) browser.DialogPolicy {
+	policy := browser.DialogPolicy{}
+	switch cfg.Policy {
+	case "auto-accept":
+		policy.Default = "accept"
+	case "auto-dismiss":
+		policy.Default = "dismiss"
+	}
+	for _, rule := range cfg.Rules {
+		action := rule.Action
+		if action == "manual" {
+			action = ""
+		}
+		policy.Rules = append(policy.Rules, browser.DialogRule{Pattern: rule.Pattern, Action: action})
+	}
+	return policy
+}
+
+func indexOfTab(tabs []*browser.Tab, tab *browser.Tab) int {
+	for i, candidate := range tabs {
+		if candidate == tab {
+			return i
+		}
+	}
+	return -1
+}
+
+// takePopupNotes drains the queued popup notes; the tool-call pipeline
+// appends them to the next response.
+func (c *browserContext) takePopupNotes() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	notes := c.popupNotes
+	c.popupNotes = nil
+	return notes
+}
+
+// removeTab drops a closed tab; if it was current, the last remaining tab
+// takes over.
+func (c *browserContext) removeTab(tab *browser.Tab) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, candidate := range c.tabs {
+		if candidate == tab {
+			c.tabs = append(c.tabs[:i], c.tabs[i+1:]...)
+			break
+		}
+	}
+	if c.currentTab == tab {
+		c.currentTab = nil
+		if len(c.tabs) > 0 {
+			c.currentTab = c.tabs[len(c.tabs)-1]
+		}
+	}
+}
+
+// downloads exposes the tracker to the tool-call pipeline, which reports
+// freshly saved files in tool responses.
+func (c *browserContext) downloads() *downloadTracker {
+	return c.downloadsList
+}
+
+// Downloads returns the session's download log for the downloads tools.
+func (c *browserContext) Downloads() []tools.DownloadEntry {
+	var entries []tools.DownloadEntry
+	for _, entry := range c.downloadsList.all() {
+		entries = append(entries, tools.DownloadEntry{
+			SuggestedFilename: entry.SuggestedFilename,
+			Path:              entry.Path,
+			Finished:          entry.Finished,
+		})
+	}
+	return entries
+}
+
+func (c *browserContext) Tabs() []*browser.Tab {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*browser.Tab(nil), c.tabs...)
+}
+
+// CurrentTab returns the active tab, opening the first one on demand so
+// tools can assume a page exists.
+func (c *browserContext) CurrentTab() (*browser.Tab, error) {
+	if _, err := c.ensureContext(); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	tab := c.currentTab
+	c.mu.Unlock()
+	if tab != nil {
+		return tab, nil
+	}
+	return c.NewTab()
+}
+
+func (c *browserContext) NewTab() (*browser.Tab, error) {
+	context, err := c.ensureContext()
+	if err != nil {
+		return nil, err
+	}
+	page, err := context.NewPage()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a new page: %w", err)
+	}
+	tab := c.adoptPage(page)
+	c.mu.Lock()
+	c.currentTab = tab
+	c.mu.Unlock()
+	return tab, nil
+}
+
+func (c *browserContext) SelectTab(index int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if index < 0 || index >= len(c.tabs) {
+		return fmt.Errorf("no tab at index %d: %d tab(s) open", index, len(c.tabs))
+	}
+	c.currentTab = c.tabs[index]
+	return nil
+}
+
+// ModalStates reports the modals blocking the current tab; other tabs'
+// modals only block once selected.
+func (c *browserContext) ModalStates() []browser.ModalState {
+	c.mu.Lock()
+	tab := c.currentTab
+	c.mu.Unlock()
+	if tab == nil {
+		return nil
+	}
+	return tab.ModalStates()
+}
+
+func (c *browserContext) GrantPermissions(permissions []string, origin string) error {
+	context, err := c.ensureContext()
+	if err != nil {
+		return err
+	}
+	options := playwright.BrowserContextGrantPermissionsOptions{}
+	if origin != "" {
+		options.Origin = playwright.String(origin)
+	}
+	return context.GrantPermissions(permissions, options)
+}
+
+// OutputDir returns the directory tool output files are written to.
+func (c *browserContext) OutputDir() string {
+	return c.config.OutputDir
+}
+
+// AllowedUploadDir returns the directory uploads are restricted to; empty
+// means any absolute path is allowed.
+func (c *browserContext) AllowedUploadDir() string {
+	return c.config.AllowedUploadDir
+}
+
+// ActionTimeout returns the configured per-action timeout.
+func (c *browserContext) ActionTimeout() time.Duration {
+	return c.config.Timeouts.Action.Std()
+}
+
+// SaveStorageState writes the context's cookies and local storage to path,
+// defaulting to storage-state.json in the output directory, and returns
+// the file written.
+func (c *browserContext) SaveStorageState(path string) (string, error) {
+	context, err := c.ensureContext()
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		path = filepath.Join(c.config.OutputDir, "storage-state.json")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if _, err := context.StorageState(path); err != nil {
+		return "", fmt.Errorf("failed to save storage state: %w", err)
+	}
+	return path, nil
+}
+
+// TracePath returns where the session trace lands on close, or "" when
+// trace recording is disabled.
+func (c *browserContext) TracePath() string {
+	if !c.config.SaveTrace {
+		return ""
+	}
+	return c.config.TracePath()
+}
+
+func (c *browserContext) InstallBrowser() error {
+	return browser.Install(c.config)
+}
+
+func (c *browserContext) LookupSecret(name string) (string, bool) {
+	return c.secrets.Lookup(name)
+}
+
+// Close releases the Playwright context, if one was ever launched, and the
+// factory behind it unless the factory is shared. When video recording is
+// enabled the webm files are finalized under config.VideosDir() as part of
+// the context close.
+func (c *browserContext) Close() error {
+	c.mu.Lock()
+	c.tabs = nil
+	c.currentTab = nil
+	if c.pwContext != nil {
+		// The trace must be exported before the context goes away; like the
+		// storage-state write below, a failure here must not stop the close.
+		if c.config.SaveTrace {
+			c.pwContext.Tracing().Stop(c.config.TracePath())
+		}
+		// Auto-persist logins for the next run when requested; this covers
+		// every context mode, not just isolated factories.
+		if c.config.Browser.SaveStorageState {
+			path := c.config.Browser.StorageState
+			if path == "" {
+				path = filepath.Join(c.config.OutputDir, "storage-state.json")
+			}
+			c.pwContext.StorageState(path)
+		}
+		c.pwContext.Close()
+		c.pwContext = nil
+	}
+	c.mu.Unlock()
+	if !c.ownsFactory {
+		return nil
+	}
+	return c.factory.Close()
+}
@@ -0,0 +1,114 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package server
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// downloadEntry records one file saved from a page-initiated download.
+type downloadEntry struct {
+	// SuggestedFilename is what the page called the file; Path is where it
+	// was actually saved under the downloads directory.
+	SuggestedFilename string
+	Path              string
+	Finished          bool
+}
+
+// downloadTracker collects downloads for one browser context. The page
+// download listener (attached when pages are created) saves each download
+// under the session's downloads directory and records it here; tool results
+// then report the saved paths so files don't silently vanish into a temp
+// profile.
+type downloadTracker struct {
+	mu       sync.Mutex
+	dir      string
+	entries  []downloadEntry
+	reported int
+}
+
+func newDownloadTracker(dir string) *downloadTracker {
+	return &downloadTracker{dir: dir}
+}
+
+// savePath reserves a collision-free path for a suggested filename, creating
+// the downloads directory on first use.
+func (t *downloadTracker) savePath(suggestedFilename string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create downloads directory: %w", err)
+	}
+	name := sanitizeFilename(suggestedFilename)
+	path := filepath.Join(t.dir, name)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			break
+		}
+		ext := filepath.Ext(name)
+		path = filepath.Join(t.dir, fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(name, ext), i, ext))
+	}
+	t.entries = append(t.entries, downloadEntry{SuggestedFilename: suggestedFilename, Path: path})
+	return path, nil
+}
+
+// finish marks the download saved at path as complete.
+func (t *downloadTracker) finish(path string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i := range t.entries {
+		if t.entries[i].Path == path {
+			t.entries[i].Finished = true
+			return
+		}
+	}
+}
+
+// takeNew returns downloads finished since the last call, so each tool
+// response mentions a saved file exactly once.
+func (t *downloadTracker) takeNew() []downloadEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var fresh []downloadEntry
+	for i := t.reported; i < len(t.entries); i++ {
+		if !t.entries[i].Finished {
+			break
+		}
+		fresh = append(fresh, t.entries[i])
+		t.reported = i + 1
+	}
+	return fresh
+}
+
+// all returns a snapshot of every download seen in this session.
+func (t *downloadTracker) all() []downloadEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]downloadEntry(nil), t.entries...)
+}
+
+// sanitizeFilename reduces a page-supplied filename to a safe base name:
+// path separators and control characters are replaced, and an empty result
+// falls back to "download".
+func sanitizeFilename(name string) string {
+	name = filepath.Base(name)
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r < 0x20, r == '/', r == '\\', r == ':', r == '*', r == '?', r == '"', r == '<', r == '>', r == '|':
+			b.WriteRune('_')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	cleaned := strings.Trim(b.String(), ". ")
+	if cleaned == "" {
+		return "download"
+	}
+	return cleaned
+}
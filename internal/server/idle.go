@@ -0,0 +1,84 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package server
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// idleWatchdog closes a connection's browser context when no tool call
+// arrives within the configured window, so abandoned agent sessions don't
+// keep headless browsers alive indefinitely.
+type idleWatchdog struct {
+	timeout time.Duration
+
+	mu           sync.Mutex
+	lastActivity time.Time
+	stop         chan struct{}
+}
+
+func newIdleWatchdog(timeout time.Duration) *idleWatchdog {
+	return &idleWatchdog{
+		timeout:      timeout,
+		lastActivity: time.Now(),
+		stop:         make(chan struct{}),
+	}
+}
+
+// touch records activity, pushing the deadline out.
+func (w *idleWatchdog) touch() {
+	w.mu.Lock()
+	w.lastActivity = time.Now()
+	w.mu.Unlock()
+}
+
+// watch fires onIdle once the connection has been inactive for the timeout,
+// then keeps watching in case activity resumes.
+func (w *idleWatchdog) watch(onIdle func(idleFor time.Duration)) {
+	ticker := time.NewTicker(w.timeout / 4)
+	defer ticker.Stop()
+	fired := false
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			idleFor := time.Since(w.lastActivity)
+			w.mu.Unlock()
+			if idleFor >= w.timeout {
+				if !fired {
+					fired = true
+					onIdle(idleFor)
+				}
+			} else {
+				fired = false
+			}
+		}
+	}
+}
+
+func (w *idleWatchdog) close() {
+	select {
+	case <-w.stop:
+	default:
+		close(w.stop)
+	}
+}
+
+// startIdleWatchdog arms the idle timeout for this connection if configured.
+func (c *Connection) startIdleWatchdog() {
+	timeout := c.server.config.Server.SessionIdleTimeout.Std()
+	if timeout <= 0 {
+		return
+	}
+	c.idle = newIdleWatchdog(timeout)
+	go c.idle.watch(func(idleFor time.Duration) {
+		message := fmt.Sprintf("session idle for %s, closing browser context", idleFor.Round(time.Second))
+		c.Log("warning", "session", message)
+		c.ctx.Close()
+	})
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/microsoft/playwright-mcp/internal/config"
+)
+
+// outputRootName is the well-known directory under the system temp dir that
+// holds one timestamped directory per run. Retention only ever deletes
+// inside a root with this name, so a custom --output-dir is never touched.
+const outputRootName = "playwright-mcp-output"
+
+// CleanupOutput enforces the output retention policy on previous run
+// directories. It runs at startup and shutdown so long-running hosts don't
+// accumulate screenshots, traces and downloads indefinitely. The current
+// run's directory is always kept.
+func CleanupOutput(cfg *config.FullConfig) error {
+	root := filepath.Dir(cfg.OutputDir)
+	if filepath.Base(root) != outputRootName {
+		return nil
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	type runDir struct {
+		path    string
+		modTime time.Time
+		size    int64
+	}
+	var runs []runDir
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(root, entry.Name())
+		if path == cfg.OutputDir {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		runs = append(runs, runDir{path: path, modTime: info.ModTime(), size: dirSize(path)})
+	}
+	// Newest first: age, count and size limits all keep recent runs.
+	sort.Slice(runs, func(i, j int) bool { return runs[i].modTime.After(runs[j].modTime) })
+
+	retention := cfg.OutputRetention
+	var totalSize int64
+	var firstErr error
+	for i, run := range runs {
+		remove := false
+		if retention.MaxAge != 0 && time.Since(run.modTime) > retention.MaxAge.Std() {
+			remove = true
+		}
+		if retention.MaxRuns != 0 && i >= retention.MaxRuns {
+			remove = true
+		}
+		totalSize += run.size
+		if retention.MaxTotalSizeMB != 0 && totalSize > retention.MaxTotalSizeMB*1024*1024 {
+			remove = true
+		}
+		if !remove {
+			continue
+		}
+		if err := os.RemoveAll(run.path); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func dirSize(path string) int64 {
+	var size int64
+	filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,93 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/microsoft/playwright-mcp/internal/config"
+)
+
+// makeRun creates a run directory under root with the given age.
+func makeRun(t *testing.T, root, name string, age time.Duration) string {
+	t.Helper()
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "page.png"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stamp := time.Now().Add(-age)
+	if err := os.Chtimes(path, stamp, stamp); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCleanupOutputMaxRuns(t *testing.T) {
+	root := filepath.Join(t.TempDir(), outputRootName)
+	current := makeRun(t, root, "run-current", 0)
+	newest := makeRun(t, root, "run-old-1", time.Minute)
+	oldest := makeRun(t, root, "run-old-2", 2*time.Minute)
+
+	cfg := &config.FullConfig{
+		OutputDir:       current,
+		OutputRetention: config.RetentionConfig{MaxRuns: 1},
+	}
+	if err := CleanupOutput(cfg); err != nil {
+		t.Fatalf("CleanupOutput: %v", err)
+	}
+	for _, path := range []string{current, newest} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s should have been kept: %v", filepath.Base(path), err)
+		}
+	}
+	if _, err := os.Stat(oldest); !os.IsNotExist(err) {
+		t.Errorf("%s should have been removed", filepath.Base(oldest))
+	}
+}
+
+func TestCleanupOutputMaxAge(t *testing.T) {
+	root := filepath.Join(t.TempDir(), outputRootName)
+	current := makeRun(t, root, "run-current", 0)
+	fresh := makeRun(t, root, "run-fresh", 10*time.Minute)
+	stale := makeRun(t, root, "run-stale", 2*time.Hour)
+
+	cfg := &config.FullConfig{
+		OutputDir:       current,
+		OutputRetention: config.RetentionConfig{MaxAge: config.Duration(time.Hour)},
+	}
+	if err := CleanupOutput(cfg); err != nil {
+		t.Fatalf("CleanupOutput: %v", err)
+	}
+	if _, err := os.Stat(fresh); err != nil {
+		t.Errorf("run-fresh is within MaxAge and should have been kept: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("run-stale is past MaxAge and should have been removed")
+	}
+}
+
+func TestCleanupOutputLeavesCustomOutputDirAlone(t *testing.T) {
+	// A custom --output-dir does not live under the well-known root, so
+	// retention must not delete its siblings no matter how old they are.
+	root := filepath.Join(t.TempDir(), "my-artifacts")
+	current := makeRun(t, root, "run-current", 0)
+	sibling := makeRun(t, root, "run-old", 48*time.Hour)
+
+	cfg := &config.FullConfig{
+		OutputDir:       current,
+		OutputRetention: config.RetentionConfig{MaxRuns: 1, MaxAge: config.Duration(time.Hour)},
+	}
+	if err := CleanupOutput(cfg); err != nil {
+		t.Fatalf("CleanupOutput: %v", err)
+	}
+	if _, err := os.Stat(sibling); err != nil {
+		t.Errorf("siblings outside %s must never be touched: %v", outputRootName, err)
+	}
+}
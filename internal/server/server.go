@@ -0,0 +1,623 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/microsoft/playwright-mcp/internal/browser"
+	"github.com/microsoft/playwright-mcp/internal/config"
+	"github.com/microsoft/playwright-mcp/internal/mcp"
+	"github.com/microsoft/playwright-mcp/internal/secrets"
+	"github.com/microsoft/playwright-mcp/internal/telemetry"
+	"github.com/microsoft/playwright-mcp/internal/tools"
+)
+
+// Server owns the tool registry and the per-connection browser state. One
+// Server instance serves all MCP connections of a process.
+type Server struct {
+	config *config.FullConfig
+	tools  []tools.Tool
+	// secrets is nil when no secrets file is configured; all output paths
+	// must route text through it before it reaches the client.
+	secrets *secrets.Store
+	// sessionLog is nil unless --save-session is enabled.
+	sessionLog *sessionLog
+	// browsers tracks the named managed browser instances sessions can
+	// attach to; exposed over the HTTP management API.
+	browsers *browser.BrowserManager
+	// factory backs every session that runs on the base config. It lives on
+	// the server, not the connection, so a warm context pool fills once and
+	// is shared across sessions instead of being rebuilt per client.
+	factory browser.ContextFactory
+
+	mu          sync.Mutex
+	connections []*Connection
+	// sharedCtx is the one context all connections use in shared context
+	// mode; nil until the first connection and in per-client mode.
+	sharedCtx *browserContext
+	draining  bool
+	inflight  sync.WaitGroup
+
+	// sessionSlots caps concurrent sessions when maxSessions is set; nil
+	// means unlimited.
+	sessionSlots chan struct{}
+	queued       int
+
+	artifactBaseURL string
+}
+
+// ErrDraining is returned to new clients while the server is shutting down.
+var ErrDraining = errors.New("server is shutting down")
+
+// ErrAtCapacity is returned when both the session limit and the waiting
+// queue are full.
+var ErrAtCapacity = errors.New("server at capacity: too many open sessions")
+
+// maxQueuedSessions bounds how many clients may wait for a session slot.
+const maxQueuedSessions = 16
+
+// NewServer creates a server for the given resolved config.
+func NewServer(cfg *config.FullConfig) (*Server, error) {
+	s := &Server{
+		config:   cfg,
+		tools:    allTools(),
+		browsers: browser.NewBrowserManager(cfg),
+		factory:  browser.NewContextFactory(cfg),
+	}
+	if cfg.SecretsFile != "" {
+		store, err := secrets.LoadFile(cfg.SecretsFile)
+		if err != nil {
+			return nil, err
+		}
+		s.secrets = store
+	}
+	if cfg.SaveSession {
+		log, err := newSessionLog(cfg.OutputDir, s.secrets)
+		if err != nil {
+			return nil, err
+		}
+		s.sessionLog = log
+	}
+	if cfg.Server.MaxSessions > 0 {
+		s.sessionSlots = make(chan struct{}, cfg.Server.MaxSessions)
+	}
+	return s, nil
+}
+
+// allTools returns every tool the server knows about, in listing order.
+func allTools() []tools.Tool {
+	return []tools.Tool{
+		tools.Snapshot,
+		tools.SnapshotExpand,
+		tools.GetText,
+		tools.GetHTML,
+		tools.Find,
+		tools.Inspect,
+		tools.Frames,
+		tools.Navigate,
+		tools.NavigateBack,
+		tools.Click,
+		tools.Type,
+		tools.PressKey,
+		tools.FileUpload,
+		tools.HandleDialog,
+		tools.Check,
+		tools.Uncheck,
+		tools.Scroll,
+		tools.Resize,
+		tools.TakeScreenshot,
+		tools.Evaluate,
+		tools.WaitFor,
+		tools.FillForm,
+		tools.NetworkRequestDetails,
+		tools.ConsoleMessages,
+		tools.GrantPermissions,
+		tools.ClipboardRead,
+		tools.ClipboardWrite,
+		tools.Install,
+		tools.ClockInstall,
+		tools.ClockSetTime,
+		tools.ClockFastForward,
+		tools.SaveStorageState,
+		tools.Downloads,
+		tools.WaitForDownload,
+		tools.Close,
+		tools.TabList,
+		tools.TabNew,
+		tools.TabSelect,
+		tools.ScreenCapture,
+		tools.ScreenMoveMouse,
+		tools.ScreenClick,
+		tools.ScreenDrag,
+		tools.ScreenType,
+	}
+}
+
+// Config returns the server's resolved configuration.
+func (s *Server) Config() *config.FullConfig {
+	return s.config
+}
+
+// ApplyRuntimeConfig copies the non-structural settings from a freshly
+// re-resolved config onto the live one. Structural options (browser choice,
+// ports, profile directory) are ignored on purpose: changing them would
+// require restarting browsers and listeners. Sessions created without
+// overrides read the live config, so network rules and response tuning take
+// effect without a restart.
+func (s *Server) ApplyRuntimeConfig(next *config.FullConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config.Network.AllowedOrigins = next.Network.AllowedOrigins
+	s.config.Network.BlockedOrigins = next.Network.BlockedOrigins
+	s.config.ImageResponses = next.ImageResponses
+	s.config.Server.ShutdownGrace = next.Server.ShutdownGrace
+	s.config.Server.SessionIdleTimeout = next.Server.SessionIdleTimeout
+	s.config.Timeouts = next.Timeouts
+}
+
+// Connection is the per-client backend: it filters tools by capability and
+// routes tool calls into the client's browser context.
+type Connection struct {
+	server *Server
+	// config is the server config with this session's overrides applied.
+	config *config.FullConfig
+	ctx    tools.Context
+	mcp    *mcp.Connection
+	idle   *idleWatchdog
+	// clientInfo is what the client sent in initialize; it feeds the
+	// "auto" image-response heuristic.
+	clientInfo mcp.Implementation
+}
+
+// Bind gives the backend its MCP connection so it can send notifications.
+// Called by mcp.NewConnection.
+func (c *Connection) Bind(conn *mcp.Connection) {
+	c.mcp = conn
+}
+
+// Log forwards a log entry to the client when the logging capability is in
+// use; entries emitted before Bind are dropped. String payloads are redacted
+// so secret values never reach the transcript via log messages.
+func (c *Connection) Log(level, logger string, data any) {
+	if c.mcp == nil {
+		return
+	}
+	if text, ok := data.(string); ok {
+		data = c.server.secrets.Redact(text)
+	}
+	c.mcp.Log(level, logger, data)
+}
+
+// NewConnection creates the backend for one MCP client, applying any
+// per-session config overrides. It fails with ErrDraining once shutdown has
+// begun and with ErrAtCapacity when the session limit and its waiting queue
+// are both full; otherwise a client over the limit waits (FIFO) for a slot
+// to free up.
+func (s *Server) NewConnection(overrides *config.SessionOverrides) (*Connection, error) {
+	s.mu.Lock()
+	if s.draining {
+		s.mu.Unlock()
+		return nil, ErrDraining
+	}
+	s.mu.Unlock()
+
+	if err := s.acquireSessionSlot(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.draining {
+		s.releaseSessionSlot()
+		return nil, ErrDraining
+	}
+	cfg := s.config.WithSessionOverrides(overrides)
+	ctx, err := s.contextFor(cfg, overrides)
+	if err != nil {
+		s.releaseSessionSlot()
+		return nil, err
+	}
+	conn := &Connection{
+		server: s,
+		config: cfg,
+		ctx:    ctx,
+	}
+	conn.startIdleWatchdog()
+	s.connections = append(s.connections, conn)
+	return conn, nil
+}
+
+// contextFor picks the browser context for a new connection. In shared
+// context mode every connection sees the same context and tabs (created
+// lazily, from the base config — per-session emulation overrides cannot
+// apply to a context other clients already use); per-client mode gives the
+// connection its own, attached to a named managed browser instance when
+// the session asked for one. Callers hold s.mu.
+func (s *Server) contextFor(cfg *config.FullConfig, overrides *config.SessionOverrides) (tools.Context, error) {
+	if overrides != nil && overrides.BrowserInstance != "" {
+		if s.config.Server.ContextMode == config.ContextModeShared {
+			return nil, fmt.Errorf("browserInstance cannot be used in shared context mode")
+		}
+		factory, err := s.browsers.Factory(overrides.BrowserInstance)
+		if err != nil {
+			return nil, err
+		}
+		return newBrowserContextWithFactory(cfg, s.secrets, factory, true), nil
+	}
+	if s.config.Server.ContextMode != config.ContextModeShared {
+		if cfg == s.config {
+			// No overrides: run on the server's shared factory so pooled
+			// warm contexts are reused instead of discarded.
+			return newBrowserContextWithFactory(cfg, s.secrets, s.factory, false), nil
+		}
+		return newBrowserContext(cfg, s.secrets), nil
+	}
+	if s.sharedCtx == nil {
+		s.sharedCtx = newBrowserContextWithFactory(s.config, s.secrets, s.factory, false)
+	}
+	return s.sharedCtx, nil
+}
+
+func (s *Server) acquireSessionSlot() error {
+	if s.sessionSlots == nil {
+		return nil
+	}
+	select {
+	case s.sessionSlots <- struct{}{}:
+		return nil
+	default:
+	}
+	s.mu.Lock()
+	if s.queued >= maxQueuedSessions {
+		s.mu.Unlock()
+		return ErrAtCapacity
+	}
+	s.queued++
+	s.mu.Unlock()
+	// Blocked senders are released in FIFO order by the runtime.
+	s.sessionSlots <- struct{}{}
+	s.mu.Lock()
+	s.queued--
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Server) releaseSessionSlot() {
+	if s.sessionSlots != nil {
+		<-s.sessionSlots
+	}
+}
+
+// Initialize implements mcp.ServerBackend. Clients may ship per-session
+// config overrides in a "playwright" member of the initialize params.
+func (c *Connection) Initialize(clientInfo mcp.Implementation, rawParams json.RawMessage) error {
+	c.clientInfo = clientInfo
+	if len(rawParams) == 0 {
+		return nil
+	}
+	var params struct {
+		Playwright *config.SessionOverrides `json:"playwright"`
+	}
+	if err := json.Unmarshal(rawParams, &params); err != nil || params.Playwright == nil {
+		return nil
+	}
+	c.config = c.config.WithSessionOverrides(params.Playwright)
+	// The shared context keeps serving everyone; overrides only affect this
+	// connection's tool filtering there.
+	if c.server.config.Server.ContextMode != config.ContextModeShared {
+		c.server.mu.Lock()
+		ctx, err := c.server.contextFor(c.config, params.Playwright)
+		c.server.mu.Unlock()
+		if err != nil {
+			return err
+		}
+		c.ctx = ctx
+	}
+	return nil
+}
+
+// ListTools implements mcp.ServerBackend. Tools are filtered by the enabled
+// capabilities and annotated with their behavior hints.
+func (c *Connection) ListTools() []mcp.Tool {
+	var result []mcp.Tool
+	for _, tool := range c.server.tools {
+		if tool.Capability != "" && !c.config.HasCapability(tool.Capability) {
+			continue
+		}
+		if !c.config.ToolAllowed(tool.Schema.Name) {
+			continue
+		}
+		result = append(result, toMCPTool(tool.Schema))
+	}
+	return result
+}
+
+// toMCPTool converts a tool schema into its wire representation, mapping the
+// schema type onto MCP tool annotations.
+func toMCPTool(schema tools.ToolSchema) mcp.Tool {
+	annotations := &mcp.ToolAnnotations{
+		Title: schema.Title,
+		// Every tool drives a real browser against arbitrary sites.
+		OpenWorldHint: boolPtr(true),
+	}
+	switch schema.Type {
+	case tools.SchemaTypeReadOnly:
+		annotations.ReadOnlyHint = true
+		annotations.DestructiveHint = boolPtr(false)
+	case tools.SchemaTypeDestructive:
+		annotations.DestructiveHint = boolPtr(true)
+	}
+	return mcp.Tool{
+		Name:        schema.Name,
+		Title:       schema.Title,
+		Description: schema.Description,
+		InputSchema: schema.InputSchema,
+		Annotations: annotations,
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+// handlesModalState reports whether a tool may run while a modal state
+// blocks the page, because it resolves (or inspects) that state.
+func handlesModalState(name string) bool {
+	switch name {
+	case "browser_handle_dialog", "browser_file_upload", "browser_tab_list", "browser_tab_select":
+		return true
+	}
+	return false
+}
+
+// CallTool implements mcp.ServerBackend.
+func (c *Connection) CallTool(name string, arguments json.RawMessage) (*mcp.CallToolResult, error) {
+	c.server.mu.Lock()
+	if c.server.draining {
+		c.server.mu.Unlock()
+		return nil, ErrDraining
+	}
+	c.server.inflight.Add(1)
+	c.server.mu.Unlock()
+	defer c.server.inflight.Done()
+	if c.idle != nil {
+		c.idle.touch()
+		defer c.idle.touch()
+	}
+
+	_, span := telemetry.StartToolSpan(context.Background(), name, arguments)
+
+	tool, err := c.lookupTool(name)
+	if err != nil {
+		telemetry.EndToolSpan(span, err)
+		return nil, err
+	}
+	// A dialog or file chooser blocks all page interaction; refuse other
+	// tools until it is handled rather than letting them time out.
+	if states := c.ctx.ModalStates(); len(states) > 0 && !handlesModalState(name) {
+		err := fmt.Errorf("the page is blocked by a modal state; handle it first\n%s", tools.RenderModalStates(states))
+		telemetry.EndToolSpan(span, err)
+		return nil, err
+	}
+	started := time.Now()
+	result, err := tool.Handle(c.ctx, arguments)
+	telemetry.EndToolSpan(span, err)
+	c.recordToolCall(name, arguments, started, result, err)
+	if err != nil {
+		c.Log("warning", "tool", fmt.Sprintf("%s failed: %v", name, err))
+		return nil, err
+	}
+	// Let requests the action triggered settle before responding (and
+	// before any snapshot), capped by the action timeout. Only applies when
+	// a tab exists; CurrentTab would otherwise open one.
+	if result != nil && result.WaitForNetwork && len(c.ctx.Tabs()) > 0 {
+		if tab, tabErr := c.ctx.CurrentTab(); tabErr == nil {
+			tab.WaitForNetworkIdle(c.config.Timeouts.Action.Std())
+		}
+	}
+	response := renderResult(result, c.server.secrets, c.allowImages())
+	// The page state travels with every result that asked for it, so the
+	// agent never acts on a stale view after a mutating tool.
+	if result != nil && result.CaptureSnapshot && len(c.ctx.Tabs()) > 0 {
+		if tab, tabErr := c.ctx.CurrentTab(); tabErr == nil {
+			if snapshot, snapErr := tab.Snapshot(); snapErr == nil {
+				snapshot = browser.TruncateSnapshot(snapshot, c.config.Snapshot.MaxChars)
+				title, _ := tab.Title()
+				pageState := fmt.Sprintf("### Page state\n- Page URL: %s\n- Page Title: %s\n- Page Snapshot:\n```yaml\n%s\n```", tab.URL(), title, snapshot)
+				response.Content = append(response.Content, mcp.NewTextContent(c.server.secrets.Redact(pageState)))
+			} else {
+				response.Content = append(response.Content, mcp.NewTextContent(fmt.Sprintf("Snapshot failed: %v", snapErr)))
+			}
+		}
+	}
+	// Modal states (dialogs, file choosers) are surfaced with every result
+	// so the agent knows the page is blocked and which tool unblocks it.
+	if states := c.ctx.ModalStates(); len(states) > 0 {
+		response.Content = append(response.Content, mcp.NewTextContent(tools.RenderModalStates(states)))
+	}
+	// Popups adopted since the last response are announced so the agent
+	// does not lose OAuth windows and target=_blank tabs.
+	if provider, ok := c.ctx.(interface{ takePopupNotes() []string }); ok {
+		for _, note := range provider.takePopupNotes() {
+			response.Content = append(response.Content, mcp.NewTextContent(note))
+		}
+	}
+	// Downloads triggered by the call are reported alongside the result so
+	// saved files don't silently pile up in the output directory.
+	if provider, ok := c.ctx.(interface{ downloads() *downloadTracker }); ok {
+		for _, entry := range provider.downloads().takeNew() {
+			message := fmt.Sprintf("Downloaded %s to %s", entry.SuggestedFilename, entry.Path)
+			if url := c.server.ArtifactURL(entry.Path); url != "" {
+				message += " (" + url + ")"
+			}
+			response.Content = append(response.Content, mcp.NewTextContent(message))
+		}
+	}
+	return response, nil
+}
+
+// recordToolCall appends the call to the session log when one is enabled.
+func (c *Connection) recordToolCall(name string, arguments json.RawMessage, started time.Time, result *tools.ToolResult, err error) {
+	if c.server.sessionLog == nil {
+		return
+	}
+	entry := sessionLogEntry{
+		Timestamp:  started,
+		Tool:       name,
+		Params:     arguments,
+		DurationMS: time.Since(started).Milliseconds(),
+	}
+	switch {
+	case err != nil:
+		entry.Result = err.Error()
+		entry.IsError = true
+	case result != nil:
+		entry.Code = append([]string(nil), result.Code...)
+		entry.IsError = result.IsError
+		for _, content := range result.Content {
+			if text, ok := content.(string); ok {
+				entry.Result = text
+				break
+			}
+		}
+	}
+	c.server.sessionLog.record(entry)
+}
+
+func (c *Connection) lookupTool(name string) (*tools.Tool, error) {
+	for i := range c.server.tools {
+		tool := &c.server.tools[i]
+		if tool.Schema.Name != name {
+			continue
+		}
+		if tool.Capability != "" && !c.config.HasCapability(tool.Capability) {
+			return nil, fmt.Errorf("tool %q is not available: capability %q is not enabled", name, tool.Capability)
+		}
+		if !c.config.ToolAllowed(name) {
+			return nil, fmt.Errorf("tool %q is not available: disabled by the tool allow/block list", name)
+		}
+		return tool, nil
+	}
+	return nil, fmt.Errorf("tool %q not found", name)
+}
+
+// allowImages decides whether inline image content may go to this client:
+// "allow" and "omit" are explicit, "auto" sends images unless the client is
+// known not to render them.
+func (c *Connection) allowImages() bool {
+	switch c.config.ImageResponses {
+	case "allow":
+		return true
+	case "omit":
+		return false
+	}
+	return !strings.Contains(strings.ToLower(c.clientInfo.Name), "cursor")
+}
+
+// renderResult turns a ToolResult into the MCP response payload. All text on
+// the way out — generated code, snapshots, console output — is redacted
+// against the secret store, and image blocks are dropped when the
+// image-response policy says the client should not receive them.
+func renderResult(result *tools.ToolResult, store *secrets.Store, allowImages bool) *mcp.CallToolResult {
+	response := &mcp.CallToolResult{IsError: result.IsError}
+	if len(result.Code) > 0 {
+		code := "```js\n" + strings.Join(result.Code, "\n") + "\n```"
+		response.Content = append(response.Content, mcp.NewTextContent(store.Redact(code)))
+	}
+	for _, content := range result.Content {
+		switch v := content.(type) {
+		case string:
+			response.Content = append(response.Content, mcp.NewTextContent(store.Redact(v)))
+		case mcp.ImageContent:
+			if allowImages {
+				response.Content = append(response.Content, v)
+			}
+		default:
+			response.Content = append(response.Content, v)
+		}
+	}
+	if len(response.Content) == 0 {
+		response.Content = []any{mcp.NewTextContent("Done")}
+	}
+	return response
+}
+
+// Close implements mcp.ServerBackend.
+func (c *Connection) Close() error {
+	if c.idle != nil {
+		c.idle.close()
+	}
+	c.server.mu.Lock()
+	for i, conn := range c.server.connections {
+		if conn == c {
+			c.server.connections = append(c.server.connections[:i], c.server.connections[i+1:]...)
+			break
+		}
+	}
+	shared := c.server.sharedCtx != nil && c.ctx == tools.Context(c.server.sharedCtx)
+	c.server.mu.Unlock()
+	var err error
+	// The shared context outlives individual connections; the server closes
+	// it on shutdown.
+	if !shared {
+		err = c.ctx.Close()
+	}
+	c.server.releaseSessionSlot()
+	return err
+}
+
+// Drain stops accepting new sessions, waits up to grace for in-flight tool
+// calls to finish, then closes all connections so contexts can flush their
+// traces and artifacts before the browser goes away.
+func (s *Server) Drain(grace time.Duration) {
+	s.mu.Lock()
+	s.draining = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(grace):
+	}
+	s.Close()
+}
+
+// Close shuts down the server and all its connections.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	connections := append([]*Connection(nil), s.connections...)
+	s.mu.Unlock()
+	for _, conn := range connections {
+		if conn.ctx != tools.Context(s.sharedCtx) {
+			conn.ctx.Close()
+		}
+	}
+	if s.sharedCtx != nil {
+		s.sharedCtx.Close()
+		s.sharedCtx = nil
+	}
+	if s.sessionLog != nil {
+		s.sessionLog.close()
+	}
+	s.factory.Close()
+	// Managed instances go last: sessions attached to them have closed
+	// their contexts by now, and CloseAll still escalates to SIGKILL for
+	// anything that will not exit.
+	return s.browsers.CloseAll()
+}
+
+// Browsers exposes the managed browser instances to the HTTP management
+// API.
+func (s *Server) Browsers() *browser.BrowserManager {
+	return s.browsers
+}
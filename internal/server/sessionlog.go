@@ -0,0 +1,94 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/microsoft/playwright-mcp/internal/secrets"
+)
+
+// sessionLogEntry is one recorded tool call.
+type sessionLogEntry struct {
+	Timestamp  time.Time       `json:"timestamp"`
+	Tool       string          `json:"tool"`
+	Params     json.RawMessage `json:"params,omitempty"`
+	Code       []string        `json:"code,omitempty"`
+	Result     string          `json:"result,omitempty"`
+	IsError    bool            `json:"isError,omitempty"`
+	DurationMS int64           `json:"durationMs"`
+}
+
+// sessionLog appends every tool call to OutputDir/session.jsonl (machine
+// readable) and session.md (human readable), giving an auditable record of
+// what the agent did. All text is redacted against the secret store before
+// it touches disk.
+type sessionLog struct {
+	mu    sync.Mutex
+	jsonl *os.File
+	md    *os.File
+	store *secrets.Store
+}
+
+func newSessionLog(outputDir string, store *secrets.Store) (*sessionLog, error) {
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	jsonl, err := os.OpenFile(filepath.Join(outputDir, "session.jsonl"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session log: %w", err)
+	}
+	md, err := os.OpenFile(filepath.Join(outputDir, "session.md"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		jsonl.Close()
+		return nil, fmt.Errorf("failed to open session log: %w", err)
+	}
+	return &sessionLog{jsonl: jsonl, md: md, store: store}, nil
+}
+
+// record writes one entry to both log files. Logging failures are swallowed:
+// an unwritable session log must not fail the tool call it describes.
+func (l *sessionLog) record(entry sessionLogEntry) {
+	entry.Result = l.store.Redact(entry.Result)
+	for i, line := range entry.Code {
+		entry.Code[i] = l.store.Redact(line)
+	}
+	if len(entry.Params) > 0 {
+		entry.Params = json.RawMessage(l.store.Redact(string(entry.Params)))
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if data, err := json.Marshal(entry); err == nil {
+		fmt.Fprintf(l.jsonl, "%s\n", data)
+	}
+
+	fmt.Fprintf(l.md, "### %s — %s (%dms)\n\n", entry.Timestamp.Format(time.RFC3339), entry.Tool, entry.DurationMS)
+	if len(entry.Params) > 0 && string(entry.Params) != "null" {
+		fmt.Fprintf(l.md, "Params: `%s`\n\n", entry.Params)
+	}
+	if len(entry.Code) > 0 {
+		fmt.Fprintf(l.md, "```js\n%s\n```\n\n", strings.Join(entry.Code, "\n"))
+	}
+	if entry.Result != "" {
+		prefix := ""
+		if entry.IsError {
+			prefix = "Error: "
+		}
+		fmt.Fprintf(l.md, "%s%s\n\n", prefix, entry.Result)
+	}
+}
+
+func (l *sessionLog) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.jsonl.Close()
+	l.md.Close()
+}
@@ -0,0 +1,72 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+// Package telemetry wires OpenTelemetry tracing for tool calls and browser
+// operations. Tracing is off unless an OTLP endpoint is configured through
+// the standard OTEL_EXPORTER_OTLP_ENDPOINT environment variable.
+package telemetry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/microsoft/playwright-mcp"
+
+// Init installs the global tracer provider when OTLP export is configured
+// and returns a shutdown function that flushes pending spans.
+func Init(ctx context.Context, serviceVersion string) (func(context.Context) error, error) {
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(sdkresource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName("playwright-mcp"),
+			semconv.ServiceVersion(serviceVersion),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// StartToolSpan opens a span for one tool invocation. arguments are hashed,
+// not recorded verbatim, so page content and credentials never leave the
+// process via telemetry.
+func StartToolSpan(ctx context.Context, toolName string, arguments []byte) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, "tool."+toolName,
+		trace.WithAttributes(
+			attribute.String("mcp.tool.name", toolName),
+			attribute.String("mcp.tool.params_digest", digest(arguments)),
+		))
+}
+
+// EndToolSpan closes a tool span, recording the error if any.
+func EndToolSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,80 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type checkParams struct {
+	Element string `json:"element"`
+	Ref     string `json:"ref"`
+}
+
+// Check sets a checkbox or radio button; Uncheck clears a checkbox. Both
+// use Playwright's check/uncheck semantics: actionability checks apply and
+// an element already in the target state is left alone.
+var Check = checkTool("browser_check", "Check", "Check a checkbox or select a radio button by its snapshot ref", true)
+
+// Uncheck clears a checkbox by its snapshot ref.
+var Uncheck = checkTool("browser_uncheck", "Uncheck", "Uncheck a checkbox by its snapshot ref", false)
+
+func checkTool(name, title, description string, checked bool) Tool {
+	return Tool{
+		Schema: ToolSchema{
+			Name:        name,
+			Title:       title,
+			Description: description,
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"element": map[string]any{
+						"type":        "string",
+						"description": "Human-readable description of the element, for the record",
+					},
+					"ref": map[string]any{
+						"type":        "string",
+						"description": "Element ref from a previous snapshot",
+					},
+				},
+				"required": []string{"element", "ref"},
+			},
+			Type: SchemaTypeDestructive,
+		},
+		Capability: "core",
+		Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+			var p checkParams
+			if err := json.Unmarshal(params, &p); err != nil {
+				return nil, fmt.Errorf("invalid parameters: %w", err)
+			}
+			if p.Ref == "" {
+				return nil, fmt.Errorf("ref is required")
+			}
+			tab, err := ctx.CurrentTab()
+			if err != nil {
+				return nil, err
+			}
+			handle, err := tab.RefHandle(p.Ref)
+			if err != nil {
+				return nil, err
+			}
+			method := "uncheck"
+			if checked {
+				method = "check"
+				err = handle.Check()
+			} else {
+				err = handle.Uncheck()
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to %s %s: %w", method, describeElement(p.Element, p.Ref), err)
+			}
+			return &ToolResult{
+				Code:            []string{fmt.Sprintf("await %s.%s();", refLocatorCode(tab, p.Ref), method)},
+				CaptureSnapshot: true,
+				WaitForNetwork:  true,
+			}, nil
+		},
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+
+	"github.com/microsoft/playwright-mcp/internal/browser"
+)
+
+type clickParams struct {
+	// Element is a human-readable description used for reporting and
+	// permission prompts; Ref targets the element.
+	Element     string   `json:"element"`
+	Ref         string   `json:"ref"`
+	Button      string   `json:"button"`
+	DoubleClick bool     `json:"doubleClick"`
+	Modifiers   []string `json:"modifiers"`
+	Force       bool     `json:"force"`
+}
+
+// Click clicks an element by its snapshot ref.
+var Click = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_click",
+		Title:       "Click",
+		Description: "Click an element on the page by its snapshot ref",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"element": map[string]any{
+					"type":        "string",
+					"description": "Human-readable description of the element, for the record",
+				},
+				"ref": map[string]any{
+					"type":        "string",
+					"description": "Element ref from a previous snapshot",
+				},
+				"button": map[string]any{
+					"type":        "string",
+					"enum":        []string{"left", "middle", "right"},
+					"description": "Mouse button to click with (default left)",
+				},
+				"doubleClick": map[string]any{
+					"type":        "boolean",
+					"description": "Double-click instead of single click",
+				},
+				"modifiers": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string", "enum": []string{"Alt", "Control", "Meta", "Shift"}},
+					"description": "Modifier keys held during the click",
+				},
+				"force": map[string]any{
+					"type":        "boolean",
+					"description": "Bypass actionability checks, e.g. for elements obscured by overlays",
+				},
+			},
+			"required": []string{"element", "ref"},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p clickParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		if p.Ref == "" {
+			return nil, fmt.Errorf("ref is required")
+		}
+		button, err := mouseButton(p.Button)
+		if err != nil {
+			return nil, err
+		}
+		modifiers, err := keyboardModifiers(p.Modifiers)
+		if err != nil {
+			return nil, err
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		handle, err := tab.RefHandle(p.Ref)
+		if err != nil {
+			return nil, err
+		}
+		if p.DoubleClick {
+			err = handle.Dblclick(playwright.ElementHandleDblclickOptions{
+				Button:    button,
+				Modifiers: modifiers,
+				Force:     playwright.Bool(p.Force),
+			})
+		} else {
+			err = handle.Click(playwright.ElementHandleClickOptions{
+				Button:    button,
+				Modifiers: modifiers,
+				Force:     playwright.Bool(p.Force),
+			})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to click %s: %w", describeElement(p.Element, p.Ref), err)
+		}
+		return &ToolResult{
+			Code:            []string{clickCode(tab, p)},
+			CaptureSnapshot: true,
+			WaitForNetwork:  true,
+		}, nil
+	},
+}
+
+// clickCode renders the Playwright statement equivalent to the click.
+func clickCode(tab *browser.Tab, p clickParams) string {
+	method := "click"
+	if p.DoubleClick {
+		method = "dblclick"
+	}
+	var opts []string
+	if p.Button == "middle" || p.Button == "right" {
+		opts = append(opts, fmt.Sprintf("button: '%s'", p.Button))
+	}
+	if len(p.Modifiers) > 0 {
+		opts = append(opts, fmt.Sprintf("modifiers: ['%s']", strings.Join(p.Modifiers, "', '")))
+	}
+	if p.Force {
+		opts = append(opts, "force: true")
+	}
+	args := ""
+	if len(opts) > 0 {
+		args = "{ " + strings.Join(opts, ", ") + " }"
+	}
+	return fmt.Sprintf("await %s.%s(%s);", refLocatorCode(tab, p.Ref), method, args)
+}
+
+// refLocatorCode renders the locator expression for a ref, preferring the
+// role and name recorded at snapshot time.
+func refLocatorCode(tab *browser.Tab, ref string) string {
+	role, name := tab.RefMeta(ref)
+	if role == "" {
+		return fmt.Sprintf("page.locator('aria-ref=%s')", ref)
+	}
+	if name == "" {
+		return fmt.Sprintf("page.getByRole('%s')", role)
+	}
+	return fmt.Sprintf("page.getByRole('%s', { name: '%s' })", role, strings.ReplaceAll(name, "'", "\\'"))
+}
+
+// describeElement names an element in errors, preferring the caller's
+// description.
+func describeElement(element, ref string) string {
+	if element != "" {
+		return fmt.Sprintf("%s (%s)", element, ref)
+	}
+	return ref
+}
+
+func mouseButton(name string) (*playwright.MouseButton, error) {
+	switch name {
+	case "", "left":
+		return playwright.MouseButtonLeft, nil
+	case "middle":
+		return playwright.MouseButtonMiddle, nil
+	case "right":
+		return playwright.MouseButtonRight, nil
+	default:
+		return nil, fmt.Errorf("unknown button %q (want left, middle or right)", name)
+	}
+}
+
+func keyboardModifiers(names []string) ([]playwright.KeyboardModifier, error) {
+	var modifiers []playwright.KeyboardModifier
+	for _, name := range names {
+		switch name {
+		case "Alt":
+			modifiers = append(modifiers, *playwright.KeyboardModifierAlt)
+		case "Control":
+			modifiers = append(modifiers, *playwright.KeyboardModifierControl)
+		case "Meta":
+			modifiers = append(modifiers, *playwright.KeyboardModifierMeta)
+		case "Shift":
+			modifiers = append(modifiers, *playwright.KeyboardModifierShift)
+		default:
+			return nil, fmt.Errorf("unknown modifier %q (want Alt, Control, Meta or Shift)", name)
+		}
+	}
+	return modifiers, nil
+}
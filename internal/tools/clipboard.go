@@ -0,0 +1,95 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/microsoft/playwright-mcp/internal/browser"
+)
+
+type clipboardWriteParams struct {
+	Text string `json:"text"`
+}
+
+// ClipboardWrite puts text on the browser clipboard, granting itself the
+// clipboard permissions first, so paste flows into rich-text editors can be
+// exercised.
+var ClipboardWrite = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_clipboard_write",
+		Title:       "Write clipboard",
+		Description: "Set the browser clipboard to the given text",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"text": map[string]any{
+					"type":        "string",
+					"description": "Text to place on the clipboard",
+				},
+			},
+			"required": []string{"text"},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p clipboardWriteParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		tab, err := clipboardTab(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tab.Page().Evaluate("text => navigator.clipboard.writeText(text)", p.Text); err != nil {
+			return nil, fmt.Errorf("failed to write the clipboard: %w", err)
+		}
+		return &ToolResult{
+			Code:    []string{fmt.Sprintf("await page.evaluate(text => navigator.clipboard.writeText(text), '%s');", p.Text)},
+			Content: []any{"Clipboard updated."},
+		}, nil
+	},
+}
+
+// ClipboardRead returns the clipboard text, for verifying copy buttons and
+// share-link flows.
+var ClipboardRead = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_clipboard_read",
+		Title:       "Read clipboard",
+		Description: "Return the text currently on the browser clipboard",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		tab, err := clipboardTab(ctx)
+		if err != nil {
+			return nil, err
+		}
+		text, err := tab.Page().Evaluate("() => navigator.clipboard.readText()")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the clipboard: %w", err)
+		}
+		value, _ := text.(string)
+		return &ToolResult{
+			Code:    []string{"await page.evaluate(() => navigator.clipboard.readText());"},
+			Content: []any{fmt.Sprintf("Clipboard contents:\n%s", value)},
+		}, nil
+	},
+}
+
+// clipboardTab grants the clipboard permissions — Chromium refuses
+// navigator.clipboard calls without them — and returns the current tab.
+func clipboardTab(ctx Context) (*browser.Tab, error) {
+	if err := ctx.GrantPermissions([]string{"clipboard-read", "clipboard-write"}, ""); err != nil {
+		return nil, fmt.Errorf("failed to grant clipboard permissions: %w", err)
+	}
+	return ctx.CurrentTab()
+}
@@ -0,0 +1,159 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// ClockInstallParams are the arguments of browser_clock_install.
+type ClockInstallParams struct {
+	// Time is the initial fake time, e.g. "2024-02-02T10:00:00"; empty
+	// starts the fake clock at the current time.
+	Time string `json:"time,omitempty"`
+}
+
+// ClockInstall replaces the page's clock with a controllable fake, the
+// prerequisite for the other clock tools.
+var ClockInstall = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_clock_install",
+		Title:       "Install fake clock",
+		Description: "Install a controllable fake clock in the page so time-dependent UI can be tested deterministically",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"time": map[string]any{"type": "string", "description": "Initial time, e.g. \"2024-02-02T10:00:00\" (optional, defaults to now)"},
+			},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "testing",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p ClockInstallParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		options := playwright.ClockInstallOptions{}
+		code := "await page.clock.install();"
+		if p.Time != "" {
+			options.Time = p.Time
+			code = fmt.Sprintf("await page.clock.install({ time: '%s' });", p.Time)
+		}
+		if err := tab.Page().Clock().Install(options); err != nil {
+			return nil, err
+		}
+		return &ToolResult{Code: []string{code}}, nil
+	},
+}
+
+// ClockSetTimeParams are the arguments of browser_clock_set_time.
+type ClockSetTimeParams struct {
+	Time string `json:"time"`
+	// Fixed freezes the clock at the given time instead of letting it keep
+	// ticking from there.
+	Fixed bool `json:"fixed,omitempty"`
+}
+
+// ClockSetTime jumps the fake clock to a specific time.
+var ClockSetTime = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_clock_set_time",
+		Title:       "Set clock time",
+		Description: "Set the page's fake clock to a specific time; requires browser_clock_install first",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"time":  map[string]any{"type": "string", "description": "Target time, e.g. \"2024-02-02T10:00:00\""},
+				"fixed": map[string]any{"type": "boolean", "description": "Freeze the clock at the target time (optional)"},
+			},
+			"required": []string{"time"},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "testing",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p ClockSetTimeParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		if p.Time == "" {
+			return nil, fmt.Errorf("browser_clock_set_time: time must not be empty")
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		clock := tab.Page().Clock()
+		if p.Fixed {
+			if err := clock.SetFixedTime(p.Time); err != nil {
+				return nil, err
+			}
+			return &ToolResult{Code: []string{fmt.Sprintf("await page.clock.setFixedTime('%s');", p.Time)}}, nil
+		}
+		if err := clock.SetSystemTime(p.Time); err != nil {
+			return nil, err
+		}
+		return &ToolResult{Code: []string{fmt.Sprintf("await page.clock.setSystemTime('%s');", p.Time)}}, nil
+	},
+}
+
+// ClockFastForwardParams are the arguments of browser_clock_fast_forward.
+// Exactly one of Ticks and Milliseconds must be given.
+type ClockFastForwardParams struct {
+	// Ticks is a clock string such as "30:00" (30 minutes) or "01:30:00".
+	Ticks string `json:"ticks,omitempty"`
+	// Milliseconds is a plain duration in milliseconds.
+	Milliseconds int `json:"milliseconds,omitempty"`
+}
+
+// ClockFastForward advances the fake clock, firing any timers due in the
+// skipped interval once.
+var ClockFastForward = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_clock_fast_forward",
+		Title:       "Fast-forward clock",
+		Description: "Fast-forward the page's fake clock by a duration; requires browser_clock_install first",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"ticks":        map[string]any{"type": "string", "description": "Duration as a clock string, e.g. \"30:00\" for 30 minutes"},
+				"milliseconds": map[string]any{"type": "number", "description": "Duration in milliseconds"},
+			},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "testing",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p ClockFastForwardParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		if (p.Ticks == "") == (p.Milliseconds == 0) {
+			return nil, fmt.Errorf("browser_clock_fast_forward: provide either ticks or milliseconds")
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		clock := tab.Page().Clock()
+		if p.Ticks != "" {
+			if err := clock.FastForward(p.Ticks); err != nil {
+				return nil, err
+			}
+			return &ToolResult{Code: []string{fmt.Sprintf("await page.clock.fastForward('%s');", p.Ticks)}, CaptureSnapshot: true}, nil
+		}
+		if err := clock.FastForward(p.Milliseconds); err != nil {
+			return nil, err
+		}
+		return &ToolResult{Code: []string{fmt.Sprintf("await page.clock.fastForward(%d);", p.Milliseconds)}, CaptureSnapshot: true}, nil
+	},
+}
@@ -0,0 +1,70 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type closeParams struct {
+	// Browser closes the whole browser context instead of just the current
+	// page, flushing traces, videos and storage state.
+	Browser bool `json:"browser"`
+}
+
+// Close ends the session cleanly: the current page by default, or the whole
+// browser on request, instead of leaving resources to the idle timeout.
+var Close = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_close",
+		Title:       "Close browser",
+		Description: "Close the current page, or the whole browser with its context, flushing traces, videos and storage state",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"browser": map[string]any{
+					"type":        "boolean",
+					"description": "Close the whole browser instead of just the current page",
+				},
+			},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p closeParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		if p.Browser {
+			tracePath := ctx.TracePath()
+			if err := ctx.Close(); err != nil {
+				return nil, fmt.Errorf("failed to close the browser: %w", err)
+			}
+			message := "Closed the browser. Videos and storage state have been flushed."
+			if tracePath != "" {
+				message = fmt.Sprintf("Closed the browser. Trace saved to %s; videos and storage state have been flushed.", tracePath)
+			}
+			return &ToolResult{
+				Code:    []string{"await browser.close();"},
+				Content: []any{message},
+			}, nil
+		}
+		if len(ctx.Tabs()) == 0 {
+			return &ToolResult{Content: []any{"No open pages."}}, nil
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		if err := tab.Close(); err != nil {
+			return nil, fmt.Errorf("failed to close the page: %w", err)
+		}
+		return &ToolResult{
+			Code:    []string{"await page.close();"},
+			Content: []any{"Closed the current page."},
+		}, nil
+	},
+}
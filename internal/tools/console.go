@@ -0,0 +1,124 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/microsoft/playwright-mcp/internal/browser"
+)
+
+type consoleParams struct {
+	// Level filters by severity: error (includes uncaught page errors),
+	// warning, or log (log, info and debug).
+	Level string `json:"level"`
+	// Since keeps only messages after this RFC 3339 timestamp; message
+	// timestamps in the output use the same format.
+	Since string `json:"since"`
+	// OnlyNew returns messages buffered since the previous call.
+	OnlyNew bool `json:"onlyNew"`
+	// Clear drops the buffered history after reporting it.
+	Clear bool `json:"clear"`
+}
+
+// ConsoleMessages reports the tab's buffered console output and uncaught
+// page errors, which would otherwise be invisible to the agent.
+var ConsoleMessages = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_console_messages",
+		Title:       "Get console messages",
+		Description: "Return console messages and page errors, optionally filtered by level or time, only new ones, and optionally clearing the buffer",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"level": map[string]any{
+					"type":        "string",
+					"enum":        []string{"error", "warning", "log"},
+					"description": "Only messages of this severity",
+				},
+				"since": map[string]any{
+					"type":        "string",
+					"description": "Only messages after this RFC 3339 timestamp",
+				},
+				"onlyNew": map[string]any{
+					"type":        "boolean",
+					"description": "Only messages buffered since the previous call",
+				},
+				"clear": map[string]any{
+					"type":        "boolean",
+					"description": "Clear the buffer after reporting",
+				},
+			},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p consoleParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		var since time.Time
+		if p.Since != "" {
+			parsed, err := time.Parse(time.RFC3339, p.Since)
+			if err != nil {
+				return nil, fmt.Errorf("invalid since timestamp %q: %w", p.Since, err)
+			}
+			since = parsed
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		var messages []browser.ConsoleMessage
+		if p.OnlyNew {
+			messages = tab.NewConsoleMessages()
+		} else {
+			messages = tab.ConsoleMessages()
+		}
+		var lines []string
+		for _, message := range messages {
+			if !matchesLevel(p.Level, message.Type) {
+				continue
+			}
+			if !since.IsZero() && message.Time.Before(since) {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("[%s] [%s] %s", message.Time.Format(time.RFC3339), strings.ToUpper(message.Type), message.Text))
+		}
+		if p.Level == "" || p.Level == "error" {
+			for _, pageError := range tab.PageErrors() {
+				lines = append(lines, fmt.Sprintf("[PAGE ERROR] %s", pageError))
+			}
+		}
+		if p.Clear {
+			tab.ClearConsole()
+		}
+		if len(lines) == 0 {
+			return &ToolResult{Content: []any{"No console messages."}}, nil
+		}
+		return &ToolResult{
+			Content: []any{fmt.Sprintf("### Console messages (%d)\n%s", len(lines), strings.Join(lines, "\n"))},
+		}, nil
+	},
+}
+
+// matchesLevel maps console message types onto the three filter levels;
+// an empty filter matches everything.
+func matchesLevel(level, messageType string) bool {
+	switch level {
+	case "":
+		return true
+	case "error":
+		return messageType == "error"
+	case "warning":
+		return messageType == "warning"
+	case "log":
+		return messageType == "log" || messageType == "info" || messageType == "debug"
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import "testing"
+
+func TestMatchesLevel(t *testing.T) {
+	tests := []struct {
+		level       string
+		messageType string
+		want        bool
+	}{
+		{"", "error", true},
+		{"", "debug", true},
+		{"error", "error", true},
+		{"error", "warning", false},
+		{"warning", "warning", true},
+		{"warning", "log", false},
+		{"log", "log", true},
+		{"log", "info", true},
+		{"log", "debug", true},
+		{"log", "error", false},
+		{"bogus", "log", false},
+	}
+	for _, tt := range tests {
+		if got := matchesLevel(tt.level, tt.messageType); got != tt.want {
+			t.Errorf("matchesLevel(%q, %q) = %v, want %v", tt.level, tt.messageType, got, tt.want)
+		}
+	}
+}
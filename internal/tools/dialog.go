@@ -0,0 +1,76 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type handleDialogParams struct {
+	Accept bool `json:"accept"`
+	// PromptText answers prompt() dialogs when accepting.
+	PromptText string `json:"promptText"`
+}
+
+// HandleDialog resolves the dialog (alert, confirm, prompt, beforeunload)
+// currently blocking the tab. Dialogs surface as a "dialog" modal state and
+// block every other tool until handled.
+var HandleDialog = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_handle_dialog",
+		Title:       "Handle dialog",
+		Description: "Accept or dismiss the dialog that is blocking the page, optionally with prompt text",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"accept": map[string]any{
+					"type":        "boolean",
+					"description": "Accept the dialog; false dismisses it",
+				},
+				"promptText": map[string]any{
+					"type":        "string",
+					"description": "Text entered into a prompt() dialog when accepting",
+				},
+			},
+			"required": []string{"accept"},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p handleDialogParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		state, err := tab.ClearModalState("dialog")
+		if err != nil {
+			return nil, err
+		}
+		if p.Accept {
+			if p.PromptText != "" {
+				err = state.Dialog.Accept(p.PromptText)
+			} else {
+				err = state.Dialog.Accept()
+			}
+		} else {
+			err = state.Dialog.Dismiss()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to handle dialog: %w", err)
+		}
+		action := "Dismissed"
+		if p.Accept {
+			action = "Accepted"
+		}
+		return &ToolResult{
+			Content:         []any{fmt.Sprintf("%s the %s dialog.", action, state.Dialog.Type())},
+			CaptureSnapshot: true,
+		}, nil
+	},
+}
@@ -0,0 +1,97 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Downloads lists every download of the session with its saved path, so
+// earlier files remain findable after their one-time mention in a tool
+// response has scrolled away.
+var Downloads = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_downloads",
+		Title:       "List downloads",
+		Description: "List the session's downloads, completed and in progress, with their saved paths",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		entries := ctx.Downloads()
+		if len(entries) == 0 {
+			return &ToolResult{Content: []any{"No downloads in this session."}}, nil
+		}
+		var lines []string
+		for _, entry := range entries {
+			state := "completed"
+			if !entry.Finished {
+				state = "in progress"
+			}
+			lines = append(lines, fmt.Sprintf("- %s (%s): %s", entry.SuggestedFilename, state, entry.Path))
+		}
+		return &ToolResult{
+			Content: []any{fmt.Sprintf("### Downloads (%d)\n%s", len(entries), strings.Join(lines, "\n"))},
+		}, nil
+	},
+}
+
+// WaitForDownload blocks until the download a previous action triggered
+// has been saved, bounded by the action timeout. Downloads start
+// asynchronously after a click, so acting on the file right away would
+// race the save.
+var WaitForDownload = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_wait_for_download",
+		Title:       "Wait for download",
+		Description: "Wait until the download triggered by a previous action finishes, returning its path and filename",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		known := finishedCount(ctx.Downloads())
+		deadline := time.Now().Add(ctx.ActionTimeout())
+		for time.Now().Before(deadline) {
+			entries := ctx.Downloads()
+			if finishedCount(entries) > known {
+				entry := lastFinished(entries)
+				return &ToolResult{
+					Content: []any{fmt.Sprintf("Downloaded %s to %s", entry.SuggestedFilename, entry.Path)},
+				}, nil
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		return nil, fmt.Errorf("no download finished within %s", ctx.ActionTimeout())
+	},
+}
+
+func finishedCount(entries []DownloadEntry) int {
+	count := 0
+	for _, entry := range entries {
+		if entry.Finished {
+			count++
+		}
+	}
+	return count
+}
+
+func lastFinished(entries []DownloadEntry) DownloadEntry {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Finished {
+			return entries[i]
+		}
+	}
+	return DownloadEntry{}
+}
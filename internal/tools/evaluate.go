@@ -0,0 +1,116 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type evaluateParams struct {
+	// Function is a JavaScript function expression, e.g. "() =>
+	// document.title" or "(element) => element.dataset.id" with a ref.
+	Function string `json:"function"`
+	Element  string `json:"element"`
+	Ref      string `json:"ref"`
+	// Frame scopes the evaluation to an iframe: a prefix from
+	// browser_frames (f2), a frame name, or a URL pattern.
+	Frame string `json:"frame"`
+}
+
+// evaluateMaxChars bounds the serialized result so a page cannot flood the
+// response.
+const evaluateMaxChars = 20_000
+
+// Evaluate runs arbitrary JavaScript against the page or a ref'd element.
+// It is the escape hatch for queries no dedicated tool covers, and is
+// gated behind the "evaluate" capability because it executes model-written
+// code in the page.
+var Evaluate = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_evaluate",
+		Title:       "Evaluate JavaScript",
+		Description: "Run a JavaScript function against the page, or against an element when a ref is given, and return the JSON-safe result",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"function": map[string]any{
+					"type":        "string",
+					"description": "Function expression; receives the element as its argument when ref is set",
+				},
+				"element": map[string]any{
+					"type":        "string",
+					"description": "Human-readable description of the element, for the record",
+				},
+				"ref": map[string]any{
+					"type":        "string",
+					"description": "Element ref from a previous snapshot to bind as the function argument",
+				},
+				"frame": map[string]any{
+					"type":        "string",
+					"description": "Frame to evaluate in: a prefix from browser_frames (f2), a frame name, or a URL pattern",
+				},
+			},
+			"required": []string{"function"},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "evaluate",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p evaluateParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		if p.Function == "" {
+			return nil, fmt.Errorf("function is required")
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		var value any
+		var code string
+		if p.Ref != "" {
+			handle, err := tab.RefHandle(p.Ref)
+			if err != nil {
+				return nil, err
+			}
+			value, err = handle.Evaluate(p.Function)
+			if err != nil {
+				// Thrown exceptions (with their stack) are a tool-level
+				// result, not a protocol failure.
+				return &ToolResult{IsError: true, Content: []any{err.Error()}}, nil
+			}
+			code = fmt.Sprintf("await %s.evaluate(%s);", refLocatorCode(tab, p.Ref), p.Function)
+		} else if p.Frame != "" {
+			frame, err := tab.FrameBySpec(p.Frame)
+			if err != nil {
+				return nil, err
+			}
+			value, err = frame.Evaluate(p.Function)
+			if err != nil {
+				return &ToolResult{IsError: true, Content: []any{err.Error()}}, nil
+			}
+			code = fmt.Sprintf("await page.frame({ url: '%s' }).evaluate(%s);", frame.URL(), p.Function)
+		} else {
+			value, err = tab.Page().Evaluate(p.Function)
+			if err != nil {
+				return &ToolResult{IsError: true, Content: []any{err.Error()}}, nil
+			}
+			code = fmt.Sprintf("await page.evaluate(%s);", p.Function)
+		}
+		serialized, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			serialized = []byte(fmt.Sprintf("%v", value))
+		}
+		text := string(serialized)
+		if len(text) > evaluateMaxChars {
+			text = fmt.Sprintf("%s\n… truncated: %d of %d chars shown", text[:evaluateMaxChars], evaluateMaxChars, len(text))
+		}
+		return &ToolResult{
+			Code:    []string{code},
+			Content: []any{fmt.Sprintf("```json\n%s\n```", text)},
+		}, nil
+	},
+}
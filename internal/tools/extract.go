@@ -0,0 +1,132 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// GetText extracts the page's main content as markdown. The ARIA snapshot
+// is the wrong shape for reading articles and raw HTML is too big; this is
+// the reading view.
+type getHTMLParams struct {
+	Ref string `json:"ref"`
+	// MaxBytes bounds the inline response; 0 uses the default.
+	MaxBytes int  `json:"maxBytes"`
+	Strip    bool `json:"strip"`
+	// SaveWhenLarge writes the full HTML to the output directory and
+	// returns the path instead of truncating.
+	SaveWhenLarge bool `json:"saveWhenLarge"`
+}
+
+// defaultHTMLMaxBytes bounds inline HTML responses when the caller does not
+// pick a limit.
+const defaultHTMLMaxBytes = 50_000
+
+// GetHTML returns raw page HTML, bounded so a heavy page cannot blow the
+// agent's context window.
+var GetHTML = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_get_html",
+		Title:       "Get page HTML",
+		Description: "Return the page's HTML (or the subtree under a ref), truncated beyond maxBytes or saved to a file with saveWhenLarge",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"ref": map[string]any{
+					"type":        "string",
+					"description": "Element ref from a previous snapshot; omit for the whole page",
+				},
+				"maxBytes": map[string]any{
+					"type":        "integer",
+					"description": "Maximum HTML bytes returned inline (default 50000)",
+				},
+				"strip": map[string]any{
+					"type":        "boolean",
+					"description": "Strip script, style, noscript and template elements",
+				},
+				"saveWhenLarge": map[string]any{
+					"type":        "boolean",
+					"description": "When the HTML exceeds maxBytes, write it to the output directory and return the path instead of truncating",
+				},
+			},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p getHTMLParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		if p.MaxBytes < 0 {
+			return nil, fmt.Errorf("maxBytes must not be negative")
+		}
+		if p.MaxBytes == 0 {
+			p.MaxBytes = defaultHTMLMaxBytes
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		html, err := tab.HTML(p.Ref, p.Strip)
+		if err != nil {
+			return nil, err
+		}
+		if len(html) <= p.MaxBytes {
+			return &ToolResult{Content: []any{html}}, nil
+		}
+		if p.SaveWhenLarge {
+			dir := ctx.OutputDir()
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return nil, fmt.Errorf("failed to create output directory: %w", err)
+			}
+			path := filepath.Join(dir, fmt.Sprintf("page-%s.html", time.Now().Format("2006-01-02T15-04-05")))
+			if err := os.WriteFile(path, []byte(html), 0o644); err != nil {
+				return nil, fmt.Errorf("failed to write HTML file: %w", err)
+			}
+			return &ToolResult{
+				Content: []any{fmt.Sprintf("HTML is %d bytes (limit %d); saved to %s", len(html), p.MaxBytes, path)},
+			}, nil
+		}
+		return &ToolResult{
+			Content: []any{fmt.Sprintf("%s\n<!-- truncated: %d of %d bytes shown -->", html[:p.MaxBytes], p.MaxBytes, len(html))},
+		}, nil
+	},
+}
+
+var GetText = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_get_text",
+		Title:       "Get page text",
+		Description: "Extract the main content of the current page as markdown, with links preserved",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		markdown, err := tab.ExtractMarkdown()
+		if err != nil {
+			return nil, err
+		}
+		if markdown == "" {
+			markdown = "(no extractable content)"
+		}
+		title, _ := tab.Title()
+		return &ToolResult{
+			Content: []any{fmt.Sprintf("# %s\n\n%s", title, markdown)},
+		}, nil
+	},
+}
@@ -0,0 +1,77 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type findParams struct {
+	Text       string            `json:"text"`
+	Role       string            `json:"role"`
+	Attributes map[string]string `json:"attributes"`
+	Limit      int               `json:"limit"`
+}
+
+// Find locates elements by text, role or attributes and returns their refs,
+// so agents can target elements on huge pages without pulling a full
+// snapshot into context.
+var Find = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_find",
+		Title:       "Find elements",
+		Description: "Find visible elements by accessible-name fragment, ARIA role and/or attribute values; returns refs usable with other tools",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"text": map[string]any{
+					"type":        "string",
+					"description": "Case-insensitive fragment of the element's accessible name",
+				},
+				"role": map[string]any{
+					"type":        "string",
+					"description": "ARIA role to match exactly, e.g. button or link",
+				},
+				"attributes": map[string]any{
+					"type":        "object",
+					"description": "Attribute values to match exactly, e.g. {\"name\": \"email\"}",
+				},
+				"limit": map[string]any{
+					"type":        "integer",
+					"description": "Maximum number of matches returned (default 20)",
+				},
+			},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p findParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		if p.Text == "" && p.Role == "" && len(p.Attributes) == 0 {
+			return nil, fmt.Errorf("at least one of text, role or attributes is required")
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		matches, err := tab.FindElements(p.Text, p.Role, p.Attributes, p.Limit)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return &ToolResult{Content: []any{"No matching elements."}}, nil
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "### Matching elements (%d)\n", len(matches))
+		for _, match := range matches {
+			fmt.Fprintf(&b, "- %s \"%s\" [ref=%s]\n", match.Role, match.Name, match.Ref)
+		}
+		return &ToolResult{Content: []any{strings.TrimRight(b.String(), "\n")}}, nil
+	},
+}
@@ -0,0 +1,134 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+type formField struct {
+	// Name describes the field for the record; Ref targets it.
+	Name string `json:"name"`
+	Ref  string `json:"ref"`
+	// Type is textbox, checkbox, radio or combobox.
+	Type string `json:"type"`
+	// Value is the text to fill, the option to select, or "true"/"false"
+	// for checkboxes.
+	Value string `json:"value"`
+}
+
+type fillFormParams struct {
+	Fields []formField `json:"fields"`
+}
+
+// FillForm fills several form fields in one call, collapsing N round-trips
+// per form into one. All refs are validated against the current snapshot
+// before anything is touched, so a stale ref fails the call without
+// half-filling the form.
+var FillForm = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_fill_form",
+		Title:       "Fill form",
+		Description: "Fill multiple form fields (text inputs, checkboxes, radios, selects) in one call",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"fields": map[string]any{
+					"type":        "array",
+					"description": "Fields to fill, in order",
+					"items": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"name": map[string]any{
+								"type":        "string",
+								"description": "Human-readable field name, for the record",
+							},
+							"ref": map[string]any{
+								"type":        "string",
+								"description": "Element ref from a previous snapshot",
+							},
+							"type": map[string]any{
+								"type":        "string",
+								"enum":        []string{"textbox", "checkbox", "radio", "combobox"},
+								"description": "Kind of field",
+							},
+							"value": map[string]any{
+								"type":        "string",
+								"description": "Text to fill, option to select, or true/false for checkboxes",
+							},
+						},
+						"required": []string{"ref", "type", "value"},
+					},
+				},
+			},
+			"required": []string{"fields"},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p fillFormParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		if len(p.Fields) == 0 {
+			return nil, fmt.Errorf("fields is required")
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range p.Fields {
+			switch field.Type {
+			case "textbox", "checkbox", "radio", "combobox":
+			default:
+				return nil, fmt.Errorf("field %s: unknown type %q (want textbox, checkbox, radio or combobox)", describeElement(field.Name, field.Ref), field.Type)
+			}
+			if err := tab.ResolveRef(field.Ref); err != nil {
+				return nil, err
+			}
+		}
+		var code []string
+		for _, field := range p.Fields {
+			handle, err := tab.RefHandle(field.Ref)
+			if err != nil {
+				return nil, err
+			}
+			locator := refLocatorCode(tab, field.Ref)
+			switch field.Type {
+			case "textbox":
+				err = handle.Fill(field.Value)
+				code = append(code, fmt.Sprintf("await %s.fill('%s');", locator, field.Value))
+			case "checkbox":
+				if field.Value == "true" {
+					err = handle.Check()
+					code = append(code, fmt.Sprintf("await %s.check();", locator))
+				} else {
+					err = handle.Uncheck()
+					code = append(code, fmt.Sprintf("await %s.uncheck();", locator))
+				}
+			case "radio":
+				err = handle.Check()
+				code = append(code, fmt.Sprintf("await %s.check();", locator))
+			case "combobox":
+				_, err = handle.SelectOption(playwright.SelectOptionValues{
+					ValuesOrLabels: &[]string{field.Value},
+				})
+				code = append(code, fmt.Sprintf("await %s.selectOption('%s');", locator, field.Value))
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to fill %s: %w", describeElement(field.Name, field.Ref), err)
+			}
+		}
+		return &ToolResult{
+			Code:            code,
+			Content:         []any{fmt.Sprintf("Filled %d field(s).", len(p.Fields))},
+			CaptureSnapshot: true,
+			WaitForNetwork:  true,
+		}, nil
+	},
+}
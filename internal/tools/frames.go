@@ -0,0 +1,49 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Frames lists the page's frames with the prefixes their snapshot refs
+// carry, so frame-scoped tools and frame-qualified refs (f1e23) can be tied
+// back to actual iframes.
+var Frames = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_frames",
+		Title:       "List frames",
+		Description: "List the page's frames with their ref prefix, name and URL",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		frames := tab.Frames()
+		var lines []string
+		for _, frame := range frames {
+			ref := frame.Ref
+			if ref == "" {
+				ref = "main"
+			}
+			line := fmt.Sprintf("- [%s] %s", ref, frame.URL)
+			if frame.Name != "" {
+				line += fmt.Sprintf(" (name: %s)", frame.Name)
+			}
+			lines = append(lines, line)
+		}
+		return &ToolResult{
+			Content: []any{fmt.Sprintf("### Frames (%d)\n%s", len(frames), strings.Join(lines, "\n"))},
+		}, nil
+	},
+}
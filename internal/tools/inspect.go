@@ -0,0 +1,87 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type inspectParams struct {
+	Ref string `json:"ref"`
+}
+
+// Inspect reports an element's exact state — attributes, value, text,
+// key computed styles and geometry — so UI assertions don't need a
+// screenshot round-trip.
+var Inspect = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_inspect",
+		Title:       "Inspect element",
+		Description: "Return an element's tag, attributes, value, text, key computed styles and bounding box",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"ref": map[string]any{
+					"type":        "string",
+					"description": "Element ref from a previous snapshot",
+				},
+			},
+			"required": []string{"ref"},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p inspectParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		if p.Ref == "" {
+			return nil, fmt.Errorf("ref is required")
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		info, err := tab.Inspect(p.Ref)
+		if err != nil {
+			return nil, err
+		}
+		var out strings.Builder
+		fmt.Fprintf(&out, "### Element %s: <%s>\n", p.Ref, info.Tag)
+		if len(info.Attributes) > 0 {
+			out.WriteString("Attributes:\n")
+			writeSortedPairs(&out, info.Attributes)
+		}
+		if info.HasValue {
+			fmt.Fprintf(&out, "Value: %q\n", info.Value)
+		}
+		if info.HasChecked {
+			fmt.Fprintf(&out, "Checked: %t\n", info.Checked)
+		}
+		if info.Text != "" {
+			fmt.Fprintf(&out, "Text: %q\n", info.Text)
+		}
+		if len(info.Styles) > 0 {
+			out.WriteString("Computed styles:\n")
+			writeSortedPairs(&out, info.Styles)
+		}
+		fmt.Fprintf(&out, "Bounding box: %d,%d %dx%d\n", info.Box.X, info.Box.Y, info.Box.Width, info.Box.Height)
+		return &ToolResult{Content: []any{out.String()}}, nil
+	},
+}
+
+func writeSortedPairs(out *strings.Builder, pairs map[string]string) {
+	names := make([]string, 0, len(pairs))
+	for name := range pairs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "- %s: %s\n", name, pairs[name])
+	}
+}
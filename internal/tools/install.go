@@ -0,0 +1,32 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+)
+
+// Install downloads the configured browser so a first-run user can recover
+// from a failed launch without leaving the session.
+var Install = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_install",
+		Title:       "Install the browser",
+		Description: "Install the browser specified in the config. Call this if you get an error about the browser not being installed.",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "install",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		if err := ctx.InstallBrowser(); err != nil {
+			return nil, err
+		}
+		return &ToolResult{
+			Content: []any{"Browser installed."},
+		}, nil
+	},
+}
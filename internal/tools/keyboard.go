@@ -0,0 +1,108 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+type pressKeyParams struct {
+	// Key presses one key or chord, e.g. Enter or Control+a; Keys presses a
+	// sequence of them in order.
+	Key  string   `json:"key"`
+	Keys []string `json:"keys"`
+	// Text types a string character by character.
+	Text string `json:"text"`
+	// Delay is the pause between keys (and characters) in milliseconds.
+	Delay float64 `json:"delay"`
+}
+
+// PressKey sends raw keyboard input to whatever has focus — no ref needed.
+// Canvas apps, in-browser terminals and games have nothing in the
+// accessibility tree to target, so element-bound typing cannot reach them.
+var PressKey = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_press_key",
+		Title:       "Press a key",
+		Description: "Send keyboard input to the focused element: a single key or chord, a key sequence, or typed text",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"key": map[string]any{
+					"type":        "string",
+					"description": "Key or chord to press, e.g. Enter, ArrowDown or Control+a",
+				},
+				"keys": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Sequence of keys to press in order",
+				},
+				"text": map[string]any{
+					"type":        "string",
+					"description": "Text to type character by character",
+				},
+				"delay": map[string]any{
+					"type":        "number",
+					"description": "Pause between keys in milliseconds",
+				},
+			},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p pressKeyParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		if p.Key == "" && len(p.Keys) == 0 && p.Text == "" {
+			return nil, fmt.Errorf("pass key, keys or text")
+		}
+		if p.Key != "" && len(p.Keys) > 0 {
+			return nil, fmt.Errorf("key and keys are mutually exclusive")
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		keyboard := tab.Page().Keyboard()
+		keys := p.Keys
+		if p.Key != "" {
+			keys = []string{p.Key}
+		}
+		var code []string
+		for i, key := range keys {
+			if i > 0 && p.Delay > 0 {
+				time.Sleep(time.Duration(p.Delay * float64(time.Millisecond)))
+			}
+			if err := keyboard.Press(key); err != nil {
+				return nil, fmt.Errorf("failed to press %q: %w", key, err)
+			}
+			code = append(code, fmt.Sprintf("await page.keyboard.press('%s');", key))
+		}
+		if p.Text != "" {
+			options := playwright.KeyboardTypeOptions{}
+			if p.Delay > 0 {
+				options.Delay = playwright.Float(p.Delay)
+			}
+			if err := keyboard.Type(p.Text, options); err != nil {
+				return nil, fmt.Errorf("failed to type text: %w", err)
+			}
+			if p.Delay > 0 {
+				code = append(code, fmt.Sprintf("await page.keyboard.type('%s', { delay: %g });", p.Text, p.Delay))
+			} else {
+				code = append(code, fmt.Sprintf("await page.keyboard.type('%s');", p.Text))
+			}
+		}
+		return &ToolResult{
+			Code:            code,
+			CaptureSnapshot: true,
+			WaitForNetwork:  true,
+		}, nil
+	},
+}
@@ -0,0 +1,131 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/microsoft/playwright-mcp/internal/browser"
+)
+
+// NavigateParams are the arguments of browser_navigate.
+type NavigateParams struct {
+	URL string `json:"url"`
+	// WaitUntil picks the lifecycle state to wait for; empty waits for
+	// load.
+	WaitUntil string `json:"waitUntil"`
+	Referer   string `json:"referer"`
+	// Timeout overrides the navigation timeout, in seconds.
+	Timeout float64 `json:"timeout"`
+}
+
+// Navigate opens a URL in the current tab and reports where it landed:
+// redirects and HTTP errors are visible in the result rather than only in
+// the snapshot.
+var Navigate = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_navigate",
+		Title:       "Navigate to a URL",
+		Description: "Navigate to a URL",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"url": map[string]any{"type": "string", "description": "The URL to navigate to"},
+				"waitUntil": map[string]any{
+					"type":        "string",
+					"enum":        []string{"load", "domcontentloaded", "networkidle", "commit"},
+					"description": "Lifecycle state to wait for (default load)",
+				},
+				"referer": map[string]any{
+					"type":        "string",
+					"description": "Referer header for the navigation",
+				},
+				"timeout": map[string]any{
+					"type":        "number",
+					"description": "Navigation timeout in seconds, overriding the default",
+				},
+			},
+			"required": []string{"url"},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p NavigateParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		result, err := tab.Navigate(p.URL, browser.NavigateOptions{
+			WaitUntil: p.WaitUntil,
+			Referer:   p.Referer,
+			Timeout:   time.Duration(p.Timeout * float64(time.Second)),
+		})
+		if err != nil {
+			return nil, err
+		}
+		content := []any{fmt.Sprintf("Navigated to %s (HTTP %d).", result.FinalURL, result.Status)}
+		if len(result.Redirects) > 0 {
+			content = append(content, fmt.Sprintf("Redirected via: %s", strings.Join(result.Redirects, " -> ")))
+		}
+		return &ToolResult{
+			Code:            []string{gotoCode(p)},
+			Content:         content,
+			CaptureSnapshot: true,
+			WaitForNetwork:  true,
+		}, nil
+	},
+}
+
+// gotoCode renders the page.goto call with only the options that were set.
+func gotoCode(p NavigateParams) string {
+	var options []string
+	if p.WaitUntil != "" {
+		options = append(options, fmt.Sprintf("waitUntil: '%s'", p.WaitUntil))
+	}
+	if p.Referer != "" {
+		options = append(options, fmt.Sprintf("referer: '%s'", p.Referer))
+	}
+	if p.Timeout > 0 {
+		options = append(options, fmt.Sprintf("timeout: %d", int(p.Timeout*1000)))
+	}
+	if len(options) == 0 {
+		return fmt.Sprintf("await page.goto('%s');", p.URL)
+	}
+	return fmt.Sprintf("await page.goto('%s', { %s });", p.URL, strings.Join(options, ", "))
+}
+
+// NavigateBack goes back in the current tab's history.
+var NavigateBack = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_navigate_back",
+		Title:       "Go back",
+		Description: "Go back to the previous page",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		if err := tab.GoBack(); err != nil {
+			return nil, err
+		}
+		return &ToolResult{
+			Code:            []string{"await page.goBack();"},
+			CaptureSnapshot: true,
+		}, nil
+	},
+}
@@ -0,0 +1,37 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import "testing"
+
+func TestGotoCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		params NavigateParams
+		want   string
+	}{
+		{
+			name:   "url only",
+			params: NavigateParams{URL: "https://example.com"},
+			want:   "await page.goto('https://example.com');",
+		},
+		{
+			name:   "wait until",
+			params: NavigateParams{URL: "https://example.com", WaitUntil: "networkidle"},
+			want:   "await page.goto('https://example.com', { waitUntil: 'networkidle' });",
+		},
+		{
+			name:   "all options",
+			params: NavigateParams{URL: "https://example.com", WaitUntil: "load", Referer: "https://ref.example.com", Timeout: 2.5},
+			want:   "await page.goto('https://example.com', { waitUntil: 'load', referer: 'https://ref.example.com', timeout: 2500 });",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gotoCode(tt.params); got != tt.want {
+				t.Errorf("gotoCode(%+v) = %q, want %q", tt.params, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type networkDetailsParams struct {
+	// ID picks a request from the tab's request log; URL instead picks the
+	// most recent request whose URL contains the substring.
+	ID  int    `json:"id"`
+	URL string `json:"url"`
+	// MaxBytes bounds the inline body; 0 uses the default.
+	MaxBytes int `json:"maxBytes"`
+	// SaveWhenLarge writes an oversized body to the output directory and
+	// returns the path instead of truncating.
+	SaveWhenLarge bool `json:"saveWhenLarge"`
+}
+
+// defaultBodyMaxBytes bounds inline request/response bodies when the caller
+// does not pick a limit.
+const defaultBodyMaxBytes = 20_000
+
+// NetworkRequestDetails returns the headers and bodies of one recorded
+// request, for debugging the API traffic behind a page. Binary bodies are
+// summarized rather than dumped, and JSON is pretty-printed.
+var NetworkRequestDetails = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_network_request_details",
+		Title:       "Get request details",
+		Description: "Return headers and bodies for a recorded network request, by id or by URL substring",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id": map[string]any{
+					"type":        "integer",
+					"description": "Request id from the request log",
+				},
+				"url": map[string]any{
+					"type":        "string",
+					"description": "URL substring; picks the most recent matching request instead of an id",
+				},
+				"maxBytes": map[string]any{
+					"type":        "integer",
+					"description": "Maximum body bytes returned inline (default 20000)",
+				},
+				"saveWhenLarge": map[string]any{
+					"type":        "boolean",
+					"description": "When a body exceeds maxBytes, write it to the output directory and return the path instead of truncating",
+				},
+			},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p networkDetailsParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		if p.ID == 0 && p.URL == "" {
+			return nil, fmt.Errorf("pass a request id or a url substring")
+		}
+		if p.MaxBytes < 0 {
+			return nil, fmt.Errorf("maxBytes must not be negative")
+		}
+		if p.MaxBytes == 0 {
+			p.MaxBytes = defaultBodyMaxBytes
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		entry, request, err := tab.FindRequest(p.ID, p.URL)
+		if err != nil {
+			return nil, err
+		}
+
+		var out strings.Builder
+		fmt.Fprintf(&out, "### Request %d: %s %s\n", entry.ID, entry.Method, entry.URL)
+		if headers, err := request.AllHeaders(); err == nil {
+			out.WriteString("#### Request headers\n")
+			writeHeaders(&out, headers)
+		}
+		if postData, err := request.PostData(); err == nil && postData != "" {
+			out.WriteString("#### Request body\n")
+			path, rendered, err := renderBody(ctx, entry.ID, "request", []byte(postData), contentTypeOf(request.Headers()), p)
+			if err != nil {
+				return nil, err
+			}
+			out.WriteString(rendered)
+			if path != "" {
+				fmt.Fprintf(&out, "Saved to %s\n", path)
+			}
+		}
+
+		response, err := request.Response()
+		switch {
+		case err != nil || response == nil:
+			if entry.Failure != "" {
+				fmt.Fprintf(&out, "#### Response\nRequest failed: %s\n", entry.Failure)
+			} else {
+				out.WriteString("#### Response\nNo response recorded (yet).\n")
+			}
+		default:
+			fmt.Fprintf(&out, "#### Response: HTTP %d\n", response.Status())
+			responseHeaders, err := response.AllHeaders()
+			if err == nil {
+				out.WriteString("#### Response headers\n")
+				writeHeaders(&out, responseHeaders)
+			}
+			body, err := response.Body()
+			if err != nil {
+				fmt.Fprintf(&out, "#### Response body\nBody is not available: %v\n", err)
+				break
+			}
+			out.WriteString("#### Response body\n")
+			path, rendered, err := renderBody(ctx, entry.ID, "response", body, responseHeaders["content-type"], p)
+			if err != nil {
+				return nil, err
+			}
+			out.WriteString(rendered)
+			if path != "" {
+				fmt.Fprintf(&out, "Saved to %s\n", path)
+			}
+		}
+		return &ToolResult{Content: []any{out.String()}}, nil
+	},
+}
+
+// writeHeaders prints headers sorted by name, one per line.
+func writeHeaders(out *strings.Builder, headers map[string]string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "- %s: %s\n", name, headers[name])
+	}
+}
+
+func contentTypeOf(headers map[string]string) string {
+	for name, value := range headers {
+		if strings.EqualFold(name, "content-type") {
+			return value
+		}
+	}
+	return ""
+}
+
+// renderBody formats one body for inline output: binary payloads become a
+// summary line, JSON is pretty-printed, and anything beyond the size cap is
+// truncated or — with saveWhenLarge — written to a file whose path is
+// returned.
+func renderBody(ctx Context, id int, kind string, body []byte, contentType string, p networkDetailsParams) (path, rendered string, err error) {
+	if isBinaryBody(body, contentType) {
+		if p.SaveWhenLarge {
+			path, err = saveBody(ctx, id, kind, body)
+			if err != nil {
+				return "", "", err
+			}
+		}
+		return path, fmt.Sprintf("Binary body, %d bytes (%s).\n", len(body), contentType), nil
+	}
+	if strings.Contains(contentType, "json") {
+		var indented bytes.Buffer
+		if json.Indent(&indented, body, "", "  ") == nil {
+			body = indented.Bytes()
+		}
+	}
+	if len(body) <= p.MaxBytes {
+		return "", "```\n" + string(body) + "\n```\n", nil
+	}
+	if p.SaveWhenLarge {
+		path, err = saveBody(ctx, id, kind, body)
+		if err != nil {
+			return "", "", err
+		}
+		return path, fmt.Sprintf("Body is %d bytes (limit %d).\n", len(body), p.MaxBytes), nil
+	}
+	return "", fmt.Sprintf("```\n%s\n```\n(truncated: %d of %d bytes shown)\n", body[:p.MaxBytes], p.MaxBytes, len(body)), nil
+}
+
+// isBinaryBody guesses binary-ness from the content type first and the
+// bytes second: null bytes or invalid UTF-8 mean not printable.
+func isBinaryBody(body []byte, contentType string) bool {
+	mime := strings.ToLower(contentType)
+	for _, textual := range []string{"text/", "json", "xml", "javascript", "x-www-form-urlencoded", "svg"} {
+		if strings.Contains(mime, textual) {
+			return false
+		}
+	}
+	if strings.HasPrefix(mime, "image/") || strings.HasPrefix(mime, "audio/") || strings.HasPrefix(mime, "video/") || strings.HasPrefix(mime, "font/") {
+		return true
+	}
+	return bytes.IndexByte(body, 0) >= 0 || !utf8.Valid(body)
+}
+
+// saveBody writes a body to the output directory and returns the path.
+func saveBody(ctx Context, id int, kind string, body []byte) (string, error) {
+	dir := ctx.OutputDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("request-%d-%s-%s.bin", id, kind, time.Now().Format("2006-01-02T15-04-05")))
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write body file: %w", err)
+	}
+	return path, nil
+}
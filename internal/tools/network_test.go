@@ -0,0 +1,31 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import "testing"
+
+func TestIsBinaryBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        []byte
+		contentType string
+		want        bool
+	}{
+		{"json", []byte(`{"ok":true}`), "application/json", false},
+		{"html", []byte("<html></html>"), "text/html; charset=utf-8", false},
+		{"svg", []byte("<svg/>"), "image/svg+xml", false},
+		{"png by type", []byte{0x89, 'P', 'N', 'G'}, "image/png", true},
+		{"font by type", []byte("anything"), "font/woff2", true},
+		{"null byte without type", []byte{'a', 0x00, 'b'}, "", true},
+		{"invalid utf8 without type", []byte{0xff, 0xfe}, "", true},
+		{"plain text without type", []byte("hello"), "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isBinaryBody(tt.body, tt.contentType); got != tt.want {
+				t.Errorf("isBinaryBody(%q, %q) = %v, want %v", tt.body, tt.contentType, got, tt.want)
+			}
+		})
+	}
+}
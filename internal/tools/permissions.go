@@ -0,0 +1,64 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// GrantPermissionsParams are the arguments of browser_grant_permissions.
+type GrantPermissionsParams struct {
+	Permissions []string `json:"permissions"`
+	// Origin limits the grant to one origin; empty grants everywhere.
+	Origin string `json:"origin,omitempty"`
+}
+
+// GrantPermissions grants browser permissions to the current context, so
+// pages that would otherwise prompt (and hang a headless run) proceed.
+var GrantPermissions = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_grant_permissions",
+		Title:       "Grant permissions",
+		Description: "Grant browser permissions such as clipboard-read, geolocation, notifications or camera to the pages in this session",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"permissions": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Permissions to grant, e.g. [\"clipboard-read\", \"geolocation\"]",
+				},
+				"origin": map[string]any{"type": "string", "description": "Only grant for this origin (optional)"},
+			},
+			"required": []string{"permissions"},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p GrantPermissionsParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		if len(p.Permissions) == 0 {
+			return nil, fmt.Errorf("browser_grant_permissions: permissions must not be empty")
+		}
+		if err := ctx.GrantPermissions(p.Permissions, p.Origin); err != nil {
+			return nil, err
+		}
+		return &ToolResult{
+			Code: []string{fmt.Sprintf("await context.grantPermissions([%s]);", quoteList(p.Permissions))},
+		}, nil
+	},
+}
+
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("'%s'", value)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,81 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/microsoft/playwright-mcp/internal/config"
+)
+
+type resizeParams struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	// Device resizes to a registered device's viewport instead of explicit
+	// dimensions.
+	Device string `json:"device"`
+}
+
+// Resize changes the current tab's viewport at runtime, so responsive
+// layouts can be checked without restarting the session. Note that only
+// the viewport resizes: the device scale factor is fixed at context
+// creation and is reported, not changed.
+var Resize = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_resize",
+		Title:       "Resize viewport",
+		Description: "Resize the viewport to explicit dimensions or a named device's screen size",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"width": map[string]any{
+					"type":        "number",
+					"description": "Viewport width in CSS pixels",
+				},
+				"height": map[string]any{
+					"type":        "number",
+					"description": "Viewport height in CSS pixels",
+				},
+				"device": map[string]any{
+					"type":        "string",
+					"description": "Device name, e.g. iPhone 15; overrides width/height",
+				},
+			},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p resizeParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		width, height := p.Width, p.Height
+		var scaleNote string
+		if p.Device != "" {
+			device, ok := config.LookupDevice(p.Device)
+			if !ok {
+				return nil, fmt.Errorf("unknown device %q", p.Device)
+			}
+			width, height = device.Viewport.Width, device.Viewport.Height
+			scaleNote = fmt.Sprintf(" %s renders at device pixel ratio %g.", p.Device, device.DeviceScaleFactor)
+		}
+		if width <= 0 || height <= 0 {
+			return nil, fmt.Errorf("pass positive width and height, or a device name")
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		if err := tab.Page().SetViewportSize(width, height); err != nil {
+			return nil, fmt.Errorf("failed to resize viewport: %w", err)
+		}
+		return &ToolResult{
+			Code:            []string{fmt.Sprintf("await page.setViewportSize({ width: %d, height: %d });", width, height)},
+			Content:         []any{fmt.Sprintf("Viewport is now %dx%d.%s", width, height, scaleNote)},
+			CaptureSnapshot: true,
+		}, nil
+	},
+}
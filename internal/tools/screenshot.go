@@ -0,0 +1,160 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+
+	"github.com/microsoft/playwright-mcp/internal/mcp"
+)
+
+type screenshotParams struct {
+	// Type is png (default) or jpeg; Quality applies to jpeg only.
+	Type    string `json:"type"`
+	Quality int    `json:"quality"`
+	// FullPage captures the whole scrollable page instead of the viewport.
+	FullPage bool `json:"fullPage"`
+	// Ref or Selector captures a single element instead of the page.
+	Ref      string `json:"ref"`
+	Selector string `json:"selector"`
+	// Filename overrides the generated file name inside the output
+	// directory.
+	Filename string `json:"filename"`
+}
+
+// TakeScreenshot captures the page or an element as an image. The file is
+// always saved under the output directory and its path returned; the image
+// itself is attached inline too, unless the image-response policy strips it
+// in the tool-call pipeline.
+var TakeScreenshot = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_take_screenshot",
+		Title:       "Take a screenshot",
+		Description: "Take a screenshot of the current page, the full page, or a single element",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"type": map[string]any{
+					"type":        "string",
+					"enum":        []string{"png", "jpeg"},
+					"description": "Image format (default png)",
+				},
+				"quality": map[string]any{
+					"type":        "number",
+					"description": "JPEG quality 0-100; ignored for png",
+				},
+				"fullPage": map[string]any{
+					"type":        "boolean",
+					"description": "Capture the full scrollable page instead of the viewport",
+				},
+				"ref": map[string]any{
+					"type":        "string",
+					"description": "Element ref from a previous snapshot, to capture only that element",
+				},
+				"selector": map[string]any{
+					"type":        "string",
+					"description": "CSS selector of the element to capture; alternative to ref",
+				},
+				"filename": map[string]any{
+					"type":        "string",
+					"description": "File name inside the output directory (default screenshot-<timestamp>)",
+				},
+			},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p screenshotParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		format := p.Type
+		if format == "" {
+			format = "png"
+		}
+		if format != "png" && format != "jpeg" {
+			return nil, fmt.Errorf("unknown type %q (want png or jpeg)", p.Type)
+		}
+		if p.Ref != "" && p.Selector != "" {
+			return nil, fmt.Errorf("ref and selector are mutually exclusive")
+		}
+		if p.FullPage && (p.Ref != "" || p.Selector != "") {
+			return nil, fmt.Errorf("fullPage cannot be combined with ref or selector")
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		dir := ctx.OutputDir()
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+		name := p.Filename
+		if name == "" {
+			name = fmt.Sprintf("screenshot-%s.%s", time.Now().Format("2006-01-02T15-04-05"), format)
+		}
+		path := filepath.Join(dir, name)
+
+		imageType := playwright.ScreenshotTypePng
+		var quality *int
+		if format == "jpeg" {
+			imageType = playwright.ScreenshotTypeJpeg
+			if p.Quality > 0 {
+				quality = playwright.Int(p.Quality)
+			}
+		}
+		var data []byte
+		var code string
+		switch {
+		case p.Ref != "":
+			handle, err := tab.RefHandle(p.Ref)
+			if err != nil {
+				return nil, err
+			}
+			data, err = handle.Screenshot(playwright.ElementHandleScreenshotOptions{
+				Path: playwright.String(path), Type: imageType, Quality: quality,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to take screenshot: %w", err)
+			}
+			code = fmt.Sprintf("await %s.screenshot({ path: '%s' });", refLocatorCode(tab, p.Ref), name)
+		case p.Selector != "":
+			handle, err := tab.Page().QuerySelector(p.Selector)
+			if err != nil || handle == nil {
+				return nil, fmt.Errorf("no element matches selector %q", p.Selector)
+			}
+			data, err = handle.Screenshot(playwright.ElementHandleScreenshotOptions{
+				Path: playwright.String(path), Type: imageType, Quality: quality,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to take screenshot: %w", err)
+			}
+			code = fmt.Sprintf("await page.locator('%s').screenshot({ path: '%s' });", p.Selector, name)
+		default:
+			data, err = tab.Page().Screenshot(playwright.PageScreenshotOptions{
+				Path: playwright.String(path), Type: imageType, Quality: quality,
+				FullPage: playwright.Bool(p.FullPage),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to take screenshot: %w", err)
+			}
+			code = fmt.Sprintf("await page.screenshot({ path: '%s', fullPage: %t });", name, p.FullPage)
+		}
+		return &ToolResult{
+			Code: []string{code},
+			Content: []any{
+				fmt.Sprintf("Took screenshot and saved it to %s", path),
+				mcp.NewImageContent(base64.StdEncoding.EncodeToString(data), "image/"+format),
+			},
+		}, nil
+	},
+}
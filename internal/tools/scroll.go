@@ -0,0 +1,102 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type scrollParams struct {
+	// Element and Ref, when set without other options, scroll the element
+	// into view. With to/delta set, Ref names the scrollable container.
+	Element string `json:"element"`
+	Ref     string `json:"ref"`
+	To      string `json:"to"`
+	DeltaX  int    `json:"deltaX"`
+	DeltaY  int    `json:"deltaY"`
+	// IntoView forces scroll-into-view even when a container ref is given.
+	IntoView bool `json:"intoView"`
+}
+
+// Scroll reaches below-the-fold and infinite-scroll content: it scrolls a
+// ref into view, or scrolls the page (or a container) by a delta or to the
+// top/bottom, reporting the new scroll position.
+var Scroll = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_scroll",
+		Title:       "Scroll",
+		Description: "Scroll an element into view, or scroll the page or a container by a delta or to top/bottom",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"element": map[string]any{
+					"type":        "string",
+					"description": "Human-readable description of the element, for the record",
+				},
+				"ref": map[string]any{
+					"type":        "string",
+					"description": "Element ref: the element to scroll into view, or the scrollable container for to/delta",
+				},
+				"to": map[string]any{
+					"type":        "string",
+					"enum":        []string{"top", "bottom"},
+					"description": "Scroll all the way to the top or bottom",
+				},
+				"deltaX": map[string]any{
+					"type":        "integer",
+					"description": "Horizontal scroll distance in pixels",
+				},
+				"deltaY": map[string]any{
+					"type":        "integer",
+					"description": "Vertical scroll distance in pixels",
+				},
+				"intoView": map[string]any{
+					"type":        "boolean",
+					"description": "Scroll the ref'd element into view",
+				},
+			},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p scrollParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		if p.To != "" && p.To != "top" && p.To != "bottom" {
+			return nil, fmt.Errorf("unknown to %q (want top or bottom)", p.To)
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		// A bare ref means "bring this element into view".
+		if p.Ref != "" && (p.IntoView || (p.To == "" && p.DeltaX == 0 && p.DeltaY == 0)) {
+			handle, err := tab.RefHandle(p.Ref)
+			if err != nil {
+				return nil, err
+			}
+			if err := handle.ScrollIntoViewIfNeeded(); err != nil {
+				return nil, fmt.Errorf("failed to scroll %s into view: %w", describeElement(p.Element, p.Ref), err)
+			}
+			return &ToolResult{
+				Code:            []string{fmt.Sprintf("await %s.scrollIntoViewIfNeeded();", refLocatorCode(tab, p.Ref))},
+				CaptureSnapshot: true,
+			}, nil
+		}
+		if p.To == "" && p.DeltaX == 0 && p.DeltaY == 0 {
+			return nil, fmt.Errorf("nothing to do: pass a ref, to, or a delta")
+		}
+		x, y, err := tab.Scroll(p.Ref, p.To, p.DeltaX, p.DeltaY)
+		if err != nil {
+			return nil, err
+		}
+		return &ToolResult{
+			Content:         []any{fmt.Sprintf("Scroll position is now %d,%d.", x, y)},
+			CaptureSnapshot: true,
+		}, nil
+	},
+}
@@ -0,0 +1,194 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/microsoft/playwright-mcp/internal/browser"
+)
+
+type snapshotParams struct {
+	Diff     bool   `json:"diff"`
+	Ref      string `json:"ref"`
+	Selector string `json:"selector"`
+	Format   string `json:"format"`
+	Geometry bool   `json:"geometry"`
+	// Per-call filter overrides; when any is set they replace the
+	// configured snapshot filter for this capture only.
+	ExcludeRoles     []string `json:"excludeRoles"`
+	ExcludeSelectors []string `json:"excludeSelectors"`
+	ExcludeAreas     []string `json:"excludeAreas"`
+}
+
+// Snapshot captures the accessibility outline of the current page. The
+// actual capture happens in the tool-call pipeline, which appends the page
+// state to every result with CaptureSnapshot set. Diff mode bypasses the
+// pipeline and reports only what changed since the tab's last snapshot.
+var Snapshot = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_snapshot",
+		Title:       "Page snapshot",
+		Description: "Capture an accessibility snapshot of the current page; interactable elements carry refs that other tools accept",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"diff": map[string]any{
+					"type":        "boolean",
+					"description": "Return only the lines added or removed since this tab's previous snapshot",
+				},
+				"format": map[string]any{
+					"type":        "string",
+					"enum":        []string{"refs", "aria"},
+					"description": "refs (default) carries element refs for other tools; aria is Playwright's ariaSnapshot() YAML for toMatchAriaSnapshot assertions",
+				},
+				"geometry": map[string]any{
+					"type":        "boolean",
+					"description": "Annotate nodes with bounding boxes and viewport visibility, and include the scroll offset",
+				},
+				"ref": map[string]any{
+					"type":        "string",
+					"description": "Snapshot only the subtree under this element ref from a previous snapshot",
+				},
+				"selector": map[string]any{
+					"type":        "string",
+					"description": "Snapshot only the subtree matching this CSS selector",
+				},
+				"excludeRoles": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "ARIA roles to drop for this capture, replacing the configured filter",
+				},
+				"excludeSelectors": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "CSS selectors to drop for this capture, replacing the configured filter",
+				},
+				"excludeAreas": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Boilerplate areas to drop for this capture: ads, cookie-banners, nav, footer, hidden",
+				},
+			},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p snapshotParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		if p.Ref != "" && p.Selector != "" {
+			return nil, fmt.Errorf("ref and selector are mutually exclusive")
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		if p.Format == "aria" {
+			aria, err := tab.AriaSnapshot()
+			if err != nil {
+				return nil, err
+			}
+			return &ToolResult{
+				Code:    []string{"await expect(page.locator('body')).toMatchAriaSnapshot(`\n" + aria + "`);"},
+				Content: []any{fmt.Sprintf("```yaml\n%s\n```", aria)},
+			}, nil
+		}
+		if p.Geometry {
+			annotated, err := tab.SnapshotGeometry()
+			if err != nil {
+				return nil, err
+			}
+			return &ToolResult{
+				Content: []any{fmt.Sprintf("```yaml\n%s\n```", annotated)},
+			}, nil
+		}
+		if p.Ref != "" || p.Selector != "" {
+			var scoped string
+			if p.Ref != "" {
+				scoped, err = tab.SnapshotSubtree(p.Ref)
+			} else {
+				scoped, err = tab.SnapshotScoped(p.Selector)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return &ToolResult{
+				Content: []any{fmt.Sprintf("```yaml\n%s\n```", scoped)},
+			}, nil
+		}
+		if len(p.ExcludeRoles)+len(p.ExcludeSelectors)+len(p.ExcludeAreas) > 0 {
+			filtered, err := tab.SnapshotWith(&browser.SnapshotFilter{
+				Roles:     p.ExcludeRoles,
+				Selectors: p.ExcludeSelectors,
+				Areas:     p.ExcludeAreas,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &ToolResult{
+				Content: []any{fmt.Sprintf("```yaml\n%s\n```", filtered)},
+			}, nil
+		}
+		if !p.Diff {
+			return &ToolResult{CaptureSnapshot: true}, nil
+		}
+		diff, err := tab.SnapshotDiff()
+		if err != nil {
+			return nil, err
+		}
+		return &ToolResult{
+			Content: []any{fmt.Sprintf("### Page state (diff)\n```diff\n%s\n```", diff)},
+		}, nil
+	},
+}
+
+type snapshotExpandParams struct {
+	Ref string `json:"ref"`
+}
+
+// SnapshotExpand returns the outline under a single ref, for fetching
+// subtrees a size-limited snapshot pruned.
+var SnapshotExpand = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_snapshot_expand",
+		Title:       "Expand snapshot subtree",
+		Description: "Capture the accessibility snapshot of the subtree under a ref, e.g. one omitted from a truncated snapshot",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"ref": map[string]any{
+					"type":        "string",
+					"description": "Element ref from a previous snapshot",
+				},
+			},
+			"required": []string{"ref"},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p snapshotExpandParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		if p.Ref == "" {
+			return nil, fmt.Errorf("ref is required")
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		subtree, err := tab.SnapshotSubtree(p.Ref)
+		if err != nil {
+			return nil, err
+		}
+		return &ToolResult{
+			Content: []any{fmt.Sprintf("```yaml\n%s\n```", subtree)},
+		}, nil
+	},
+}
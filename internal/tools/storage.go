@@ -0,0 +1,48 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SaveStorageStateParams are the arguments of browser_save_storage_state.
+type SaveStorageStateParams struct {
+	// Filename overrides the default storage-state.json in the output
+	// directory.
+	Filename string `json:"filename,omitempty"`
+}
+
+// SaveStorageState writes the session's cookies and local storage to disk,
+// so a login performed once can seed later runs via browser.storageState.
+var SaveStorageState = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_save_storage_state",
+		Title:       "Save storage state",
+		Description: "Save the browser's cookies and local storage to a file so logins can be reused in later sessions",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"filename": map[string]any{"type": "string", "description": "File to write (optional, defaults to storage-state.json in the output directory)"},
+			},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p SaveStorageStateParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		path, err := ctx.SaveStorageState(p.Filename)
+		if err != nil {
+			return nil, err
+		}
+		return &ToolResult{
+			Code:    []string{fmt.Sprintf("await context.storageState({ path: '%s' });", path)},
+			Content: []any{fmt.Sprintf("Saved storage state to %s", path)},
+		}, nil
+	},
+}
@@ -0,0 +1,122 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/microsoft/playwright-mcp/internal/browser"
+)
+
+// TabSelectParams are the arguments of browser_tab_select.
+type TabSelectParams struct {
+	Index int `json:"index"`
+}
+
+// TabList lists the open tabs.
+var TabList = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_tab_list",
+		Title:       "List tabs",
+		Description: "List browser tabs",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "tabs",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		tabs := ctx.Tabs()
+		if len(tabs) == 0 {
+			return &ToolResult{
+				Content: []any{"No open tabs. Use browser_navigate to open a page."},
+			}, nil
+		}
+		// Tabs exist, so CurrentTab returns one without opening a page.
+		current, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		return &ToolResult{
+			Content: []any{RenderTabs(tabs, current)},
+		}, nil
+	},
+}
+
+// RenderTabs renders the tab-list markdown shared by the tab tools and
+// snapshot responses, marking the current tab.
+func RenderTabs(tabs []*browser.Tab, current *browser.Tab) string {
+	lines := []string{"### Open tabs"}
+	for i, tab := range tabs {
+		marker := ""
+		if tab == current {
+			marker = " (current)"
+		}
+		title, _ := tab.Title()
+		lines = append(lines, fmt.Sprintf("- %d:%s [%s] (%s)", i, marker, title, tab.URL()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// RenderModalStates renders the modals blocking the page; tools include it
+// when they cannot run until the agent clears a dialog or file chooser.
+func RenderModalStates(states []browser.ModalState) string {
+	lines := []string{"### Modal state"}
+	for _, state := range states {
+		lines = append(lines, fmt.Sprintf("- [%s] %s", state.Type, state.Description))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// TabNew opens a new tab.
+var TabNew = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_tab_new",
+		Title:       "Open a new tab",
+		Description: "Open a new tab",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "tabs",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		if _, err := ctx.NewTab(); err != nil {
+			return nil, err
+		}
+		return &ToolResult{CaptureSnapshot: true}, nil
+	},
+}
+
+// TabSelect switches to the tab at the given index.
+var TabSelect = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_tab_select",
+		Title:       "Select a tab",
+		Description: "Select a tab by index",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"index": map[string]any{"type": "number", "description": "The index of the tab to select"},
+			},
+			"required": []string{"index"},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "tabs",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p TabSelectParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		if err := ctx.SelectTab(p.Index); err != nil {
+			return nil, err
+		}
+		return &ToolResult{CaptureSnapshot: true}, nil
+	},
+}
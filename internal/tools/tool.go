@@ -0,0 +1,111 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/microsoft/playwright-mcp/internal/browser"
+)
+
+// SchemaType classifies what a tool does to the page so clients can apply
+// confirmation policies.
+type SchemaType string
+
+const (
+	// SchemaTypeReadOnly marks tools that only observe browser state.
+	SchemaTypeReadOnly SchemaType = "readOnly"
+	// SchemaTypeDestructive marks tools that change browser or page state.
+	SchemaTypeDestructive SchemaType = "destructive"
+)
+
+// ToolSchema describes one tool as advertised over MCP.
+type ToolSchema struct {
+	Name        string
+	Title       string
+	Description string
+	InputSchema map[string]any
+	Type        SchemaType
+}
+
+// ToolResult is the outcome of a tool invocation before it is rendered into
+// an MCP tools/call response.
+type ToolResult struct {
+	// Code is the Playwright code equivalent to what the tool did, one
+	// statement per entry.
+	Code []string
+	// Content is extra content blocks to include verbatim in the response.
+	Content []any
+	// CaptureSnapshot requests that a fresh page snapshot is appended to the
+	// response.
+	CaptureSnapshot bool
+	// WaitForNetwork requests that requests triggered by the action settle
+	// before the snapshot is captured.
+	WaitForNetwork bool
+	// IsError marks the result as a tool-level failure.
+	IsError bool
+}
+
+// Tool couples a schema with its handler. Tools are registered with the
+// server and filtered by capability.
+type Tool struct {
+	Schema ToolSchema
+	// Capability gates the tool: it is only listed when the capability is
+	// enabled in the config (empty means always available).
+	Capability string
+	Handle     func(ctx Context, params json.RawMessage) (*ToolResult, error)
+}
+
+// DownloadEntry describes one page-initiated download: what the page
+// called the file and where it was saved.
+type DownloadEntry struct {
+	SuggestedFilename string
+	Path              string
+	Finished          bool
+}
+
+// Context is the per-connection browser state handed to tool handlers.
+type Context interface {
+	// Tabs returns all open tabs.
+	Tabs() []*browser.Tab
+	// CurrentTab returns the active tab, opening one if needed.
+	CurrentTab() (*browser.Tab, error)
+	// NewTab opens a tab and makes it current.
+	NewTab() (*browser.Tab, error)
+	// SelectTab makes the tab at the given index current.
+	SelectTab(index int) error
+	// ModalStates returns the modal states (dialogs, file choosers) that
+	// currently block page interaction.
+	ModalStates() []browser.ModalState
+	// GrantPermissions grants browser permissions, optionally scoped to one
+	// origin.
+	GrantPermissions(permissions []string, origin string) error
+	// InstallBrowser downloads the configured browser into Playwright's
+	// registry, blocking until the download completes.
+	InstallBrowser() error
+	// OutputDir returns the directory tool output files are written to.
+	OutputDir() string
+	// TracePath returns where the session trace is written when the context
+	// closes, or "" when trace recording is disabled.
+	TracePath() string
+	// ActionTimeout returns the configured per-action timeout, bounding
+	// waits inside tool handlers.
+	ActionTimeout() time.Duration
+	// AllowedUploadDir returns the directory browser_file_upload may read
+	// from; empty allows any absolute path.
+	AllowedUploadDir() string
+	// Downloads returns every download of the session, oldest first.
+	Downloads() []DownloadEntry
+	// SaveStorageState writes cookies and local storage to path (empty
+	// picks a default under the output directory) and returns the file
+	// written.
+	SaveStorageState(path string) (string, error)
+	// LookupSecret resolves a secret reference from the operator's secrets
+	// file; typing tools use it so credential values never appear in tool
+	// arguments or results.
+	LookupSecret(name string) (string, bool)
+	// Close releases the browser context backing this connection.
+	Close() error
+}
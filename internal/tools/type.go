@@ -0,0 +1,125 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+type typeParams struct {
+	Element string `json:"element"`
+	Ref     string `json:"ref"`
+	Text    string `json:"text"`
+	// Secret names an entry in the operator's secrets file to type instead
+	// of literal text, so credentials never appear in tool arguments.
+	Secret string `json:"secret"`
+	// Slowly types key by key, firing per-key events for autocomplete
+	// widgets; the default fills the value in one operation.
+	Slowly bool `json:"slowly"`
+	// Submit presses Enter after typing.
+	Submit bool `json:"submit"`
+}
+
+// Type fills a ref'd element with text, clearing its previous value first.
+var Type = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_type",
+		Title:       "Type text",
+		Description: "Type text into an editable element by its snapshot ref, replacing its current value",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"element": map[string]any{
+					"type":        "string",
+					"description": "Human-readable description of the element, for the record",
+				},
+				"ref": map[string]any{
+					"type":        "string",
+					"description": "Element ref from a previous snapshot",
+				},
+				"text": map[string]any{
+					"type":        "string",
+					"description": "Text to type",
+				},
+				"secret": map[string]any{
+					"type":        "string",
+					"description": "Name of a secret from the secrets file to type instead of text",
+				},
+				"slowly": map[string]any{
+					"type":        "boolean",
+					"description": "Type one key at a time, firing per-key events (for autocomplete widgets)",
+				},
+				"submit": map[string]any{
+					"type":        "boolean",
+					"description": "Press Enter after typing",
+				},
+			},
+			"required": []string{"element", "ref"},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p typeParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		if p.Ref == "" {
+			return nil, fmt.Errorf("ref is required")
+		}
+		if p.Text != "" && p.Secret != "" {
+			return nil, fmt.Errorf("text and secret are mutually exclusive")
+		}
+		value := p.Text
+		display := fmt.Sprintf("'%s'", strings.ReplaceAll(p.Text, "'", "\\'"))
+		if p.Secret != "" {
+			secret, ok := ctx.LookupSecret(p.Secret)
+			if !ok {
+				return nil, fmt.Errorf("unknown secret %q; check the secrets file", p.Secret)
+			}
+			value = secret
+			display = fmt.Sprintf("'<secret:%s>'", p.Secret)
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		handle, err := tab.RefHandle(p.Ref)
+		if err != nil {
+			return nil, err
+		}
+		locator := refLocatorCode(tab, p.Ref)
+		var code []string
+		if p.Slowly {
+			// Clear first so slow typing still replaces the old value.
+			if err := handle.Fill(""); err != nil {
+				return nil, fmt.Errorf("failed to clear %s: %w", describeElement(p.Element, p.Ref), err)
+			}
+			if err := handle.Type(value, playwright.ElementHandleTypeOptions{Delay: playwright.Float(75)}); err != nil {
+				return nil, fmt.Errorf("failed to type into %s: %w", describeElement(p.Element, p.Ref), err)
+			}
+			code = append(code, fmt.Sprintf("await %s.pressSequentially(%s);", locator, display))
+		} else {
+			if err := handle.Fill(value); err != nil {
+				return nil, fmt.Errorf("failed to fill %s: %w", describeElement(p.Element, p.Ref), err)
+			}
+			code = append(code, fmt.Sprintf("await %s.fill(%s);", locator, display))
+		}
+		if p.Submit {
+			if err := handle.Press("Enter"); err != nil {
+				return nil, fmt.Errorf("failed to submit %s: %w", describeElement(p.Element, p.Ref), err)
+			}
+			code = append(code, fmt.Sprintf("await %s.press('Enter');", locator))
+		}
+		return &ToolResult{
+			Code:            code,
+			CaptureSnapshot: true,
+			WaitForNetwork:  true,
+		}, nil
+	},
+}
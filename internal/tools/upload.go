@@ -0,0 +1,89 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type fileUploadParams struct {
+	Paths []string `json:"paths"`
+}
+
+// FileUpload feeds files into the file chooser currently blocking the tab.
+// The chooser surfaces as a "fileChooser" modal state when a page opens
+// one; this tool resolves it.
+var FileUpload = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_file_upload",
+		Title:       "Upload files",
+		Description: "Supply one or more absolute file paths to the file chooser that is blocking the page",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"paths": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Absolute paths of the files to upload",
+				},
+			},
+			"required": []string{"paths"},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p fileUploadParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		if len(p.Paths) == 0 {
+			return nil, fmt.Errorf("paths is required")
+		}
+		for _, path := range p.Paths {
+			if err := validateUploadPath(path, ctx.AllowedUploadDir()); err != nil {
+				return nil, err
+			}
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		state, err := tab.ClearModalState("fileChooser")
+		if err != nil {
+			return nil, err
+		}
+		if err := state.FileChooser.SetFiles(p.Paths); err != nil {
+			return nil, fmt.Errorf("failed to set files: %w", err)
+		}
+		return &ToolResult{
+			Code:            []string{fmt.Sprintf("await fileChooser.setFiles(['%s']);", strings.Join(p.Paths, "', '"))},
+			Content:         []any{fmt.Sprintf("Uploaded %d file(s).", len(p.Paths))},
+			CaptureSnapshot: true,
+			WaitForNetwork:  true,
+		}, nil
+	},
+}
+
+// validateUploadPath requires an absolute path to an existing file and,
+// when an upload root is configured, confines the path to it.
+func validateUploadPath(path, root string) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("upload path %q must be absolute", path)
+	}
+	if root != "" {
+		rel, err := filepath.Rel(root, filepath.Clean(path))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("upload path %q is outside the allowed upload directory %s", path, root)
+		}
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("upload path %q: %w", path, err)
+	}
+	return nil
+}
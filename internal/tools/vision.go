@@ -0,0 +1,270 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/playwright-community/playwright-go"
+
+	"github.com/microsoft/playwright-mcp/internal/mcp"
+)
+
+// The screen_* tools are the coordinate-based alternative to the ref-based
+// tool set, for models that operate on screenshots and pixel positions
+// instead of accessibility snapshots. They are gated behind the "vision"
+// capability; after an action the model is expected to call
+// browser_screen_capture again rather than read a snapshot.
+
+type screenCoordinateParams struct {
+	// Element describes what is at the coordinates, for the record.
+	Element string  `json:"element"`
+	X       float64 `json:"x"`
+	Y       float64 `json:"y"`
+}
+
+// ScreenCapture returns a viewport screenshot for coordinate-based
+// operation. JPEG keeps the payload small; exact pixels rarely matter.
+var ScreenCapture = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_screen_capture",
+		Title:       "Capture screen",
+		Description: "Take a screenshot of the viewport for coordinate-based interaction",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "vision",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		data, err := tab.Page().Screenshot(playwright.PageScreenshotOptions{
+			Type: playwright.ScreenshotTypeJpeg, Quality: playwright.Int(50),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to capture the screen: %w", err)
+		}
+		return &ToolResult{
+			Code:    []string{"await page.screenshot({ type: 'jpeg', quality: 50 });"},
+			Content: []any{mcp.NewImageContent(base64.StdEncoding.EncodeToString(data), "image/jpeg")},
+		}, nil
+	},
+}
+
+// ScreenMoveMouse moves the mouse without clicking, for hover states.
+var ScreenMoveMouse = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_screen_move_mouse",
+		Title:       "Move mouse",
+		Description: "Move the mouse to the given viewport coordinates",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"element": map[string]any{
+					"type":        "string",
+					"description": "Human-readable description of what is at the position, for the record",
+				},
+				"x": map[string]any{"type": "number", "description": "X coordinate in the viewport"},
+				"y": map[string]any{"type": "number", "description": "Y coordinate in the viewport"},
+			},
+			"required": []string{"x", "y"},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "vision",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p screenCoordinateParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		if err := tab.Page().Mouse().Move(p.X, p.Y); err != nil {
+			return nil, fmt.Errorf("failed to move the mouse: %w", err)
+		}
+		return &ToolResult{
+			Code: []string{fmt.Sprintf("await page.mouse.move(%g, %g);", p.X, p.Y)},
+		}, nil
+	},
+}
+
+// ScreenClick clicks at viewport coordinates.
+var ScreenClick = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_screen_click",
+		Title:       "Click at coordinates",
+		Description: "Click at the given viewport coordinates",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"element": map[string]any{
+					"type":        "string",
+					"description": "Human-readable description of what is clicked, for the record",
+				},
+				"x": map[string]any{"type": "number", "description": "X coordinate in the viewport"},
+				"y": map[string]any{"type": "number", "description": "Y coordinate in the viewport"},
+			},
+			"required": []string{"x", "y"},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "vision",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p screenCoordinateParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		mouse := tab.Page().Mouse()
+		if err := mouse.Move(p.X, p.Y); err != nil {
+			return nil, fmt.Errorf("failed to click: %w", err)
+		}
+		if err := mouse.Down(); err != nil {
+			return nil, fmt.Errorf("failed to click: %w", err)
+		}
+		if err := mouse.Up(); err != nil {
+			return nil, fmt.Errorf("failed to click: %w", err)
+		}
+		return &ToolResult{
+			Code:           []string{fmt.Sprintf("await page.mouse.click(%g, %g);", p.X, p.Y)},
+			WaitForNetwork: true,
+		}, nil
+	},
+}
+
+type screenDragParams struct {
+	Element string  `json:"element"`
+	StartX  float64 `json:"startX"`
+	StartY  float64 `json:"startY"`
+	EndX    float64 `json:"endX"`
+	EndY    float64 `json:"endY"`
+}
+
+// ScreenDrag presses at the start coordinates and releases at the end, for
+// sliders, canvases and drag-and-drop.
+var ScreenDrag = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_screen_drag",
+		Title:       "Drag mouse",
+		Description: "Drag the mouse from one viewport position to another",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"element": map[string]any{
+					"type":        "string",
+					"description": "Human-readable description of what is dragged, for the record",
+				},
+				"startX": map[string]any{"type": "number", "description": "Drag start X coordinate"},
+				"startY": map[string]any{"type": "number", "description": "Drag start Y coordinate"},
+				"endX":   map[string]any{"type": "number", "description": "Drag end X coordinate"},
+				"endY":   map[string]any{"type": "number", "description": "Drag end Y coordinate"},
+			},
+			"required": []string{"startX", "startY", "endX", "endY"},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "vision",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p screenDragParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		mouse := tab.Page().Mouse()
+		if err := mouse.Move(p.StartX, p.StartY); err != nil {
+			return nil, fmt.Errorf("failed to drag: %w", err)
+		}
+		if err := mouse.Down(); err != nil {
+			return nil, fmt.Errorf("failed to drag: %w", err)
+		}
+		if err := mouse.Move(p.EndX, p.EndY); err != nil {
+			return nil, fmt.Errorf("failed to drag: %w", err)
+		}
+		if err := mouse.Up(); err != nil {
+			return nil, fmt.Errorf("failed to drag: %w", err)
+		}
+		return &ToolResult{
+			Code: []string{
+				fmt.Sprintf("await page.mouse.move(%g, %g);", p.StartX, p.StartY),
+				"await page.mouse.down();",
+				fmt.Sprintf("await page.mouse.move(%g, %g);", p.EndX, p.EndY),
+				"await page.mouse.up();",
+			},
+			WaitForNetwork: true,
+		}, nil
+	},
+}
+
+type screenTypeParams struct {
+	Text   string `json:"text"`
+	Submit bool   `json:"submit"`
+}
+
+// ScreenType types into whatever has focus, the vision-mode counterpart of
+// browser_type.
+var ScreenType = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_screen_type",
+		Title:       "Type text",
+		Description: "Type text into the focused element, optionally pressing Enter afterwards",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"text": map[string]any{
+					"type":        "string",
+					"description": "Text to type",
+				},
+				"submit": map[string]any{
+					"type":        "boolean",
+					"description": "Press Enter after typing",
+				},
+			},
+			"required": []string{"text"},
+		},
+		Type: SchemaTypeDestructive,
+	},
+	Capability: "vision",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p screenTypeParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		if p.Text == "" {
+			return nil, fmt.Errorf("text is required")
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		keyboard := tab.Page().Keyboard()
+		if err := keyboard.Type(p.Text); err != nil {
+			return nil, fmt.Errorf("failed to type text: %w", err)
+		}
+		code := []string{fmt.Sprintf("await page.keyboard.type('%s');", p.Text)}
+		if p.Submit {
+			if err := keyboard.Press("Enter"); err != nil {
+				return nil, fmt.Errorf("failed to press Enter: %w", err)
+			}
+			code = append(code, "await page.keyboard.press('Enter');")
+		}
+		return &ToolResult{
+			Code:           code,
+			WaitForNetwork: true,
+		}, nil
+	},
+}
@@ -0,0 +1,150 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+type waitForParams struct {
+	// Text waits until the text is visible; TextGone until it is not.
+	Text     string `json:"text"`
+	TextGone string `json:"textGone"`
+	// Selector waits for a CSS selector; State picks visible (default) or
+	// hidden.
+	Selector string `json:"selector"`
+	State    string `json:"state"`
+	// URL waits until the page URL matches the glob pattern.
+	URL string `json:"url"`
+	// Time waits a fixed number of seconds.
+	Time float64 `json:"time"`
+	// Frame scopes text and selector waits to an iframe: a prefix from
+	// browser_frames (f2), a frame name, or a URL pattern.
+	Frame string `json:"frame"`
+}
+
+// WaitFor blocks until a page condition holds, bounded by the configured
+// action timeout, so agents don't paper over async UI with blind sleeps.
+var WaitFor = Tool{
+	Schema: ToolSchema{
+		Name:        "browser_wait_for",
+		Title:       "Wait for",
+		Description: "Wait until text appears or disappears, a selector becomes visible or hidden, the URL matches a pattern, or a fixed time elapses",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"text": map[string]any{
+					"type":        "string",
+					"description": "Wait until this text is visible on the page",
+				},
+				"textGone": map[string]any{
+					"type":        "string",
+					"description": "Wait until this text is no longer visible",
+				},
+				"selector": map[string]any{
+					"type":        "string",
+					"description": "Wait for the element matching this CSS selector",
+				},
+				"state": map[string]any{
+					"type":        "string",
+					"enum":        []string{"visible", "hidden"},
+					"description": "Target state for selector waits (default visible)",
+				},
+				"url": map[string]any{
+					"type":        "string",
+					"description": "Wait until the page URL matches this glob pattern, e.g. **/checkout",
+				},
+				"time": map[string]any{
+					"type":        "number",
+					"description": "Wait this many seconds",
+				},
+				"frame": map[string]any{
+					"type":        "string",
+					"description": "Frame to wait in: a prefix from browser_frames (f2), a frame name, or a URL pattern",
+				},
+			},
+		},
+		Type: SchemaTypeReadOnly,
+	},
+	Capability: "core",
+	Handle: func(ctx Context, params json.RawMessage) (*ToolResult, error) {
+		var p waitForParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+		if p.Text == "" && p.TextGone == "" && p.Selector == "" && p.URL == "" && p.Time <= 0 {
+			return nil, fmt.Errorf("nothing to wait for: pass text, textGone, selector, url or time")
+		}
+		if p.State != "" && p.State != "visible" && p.State != "hidden" {
+			return nil, fmt.Errorf("unknown state %q (want visible or hidden)", p.State)
+		}
+		tab, err := ctx.CurrentTab()
+		if err != nil {
+			return nil, err
+		}
+		timeout := playwright.Float(float64(ctx.ActionTimeout().Milliseconds()))
+		// Text and selector waits run in the main frame unless scoped.
+		frame, err := tab.FrameBySpec(p.Frame)
+		if err != nil {
+			return nil, err
+		}
+		var code []string
+		if p.Time > 0 {
+			capped := time.Duration(p.Time * float64(time.Second))
+			if limit := ctx.ActionTimeout(); capped > limit {
+				capped = limit
+			}
+			time.Sleep(capped)
+			code = append(code, fmt.Sprintf("await page.waitForTimeout(%d);", capped.Milliseconds()))
+		}
+		if p.Text != "" {
+			err := frame.GetByText(p.Text).First().WaitFor(playwright.LocatorWaitForOptions{
+				State:   playwright.WaitForSelectorStateVisible,
+				Timeout: timeout,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("text %q did not appear: %w", p.Text, err)
+			}
+			code = append(code, fmt.Sprintf("await page.getByText('%s').first().waitFor({ state: 'visible' });", p.Text))
+		}
+		if p.TextGone != "" {
+			err := frame.GetByText(p.TextGone).First().WaitFor(playwright.LocatorWaitForOptions{
+				State:   playwright.WaitForSelectorStateHidden,
+				Timeout: timeout,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("text %q did not disappear: %w", p.TextGone, err)
+			}
+			code = append(code, fmt.Sprintf("await page.getByText('%s').first().waitFor({ state: 'hidden' });", p.TextGone))
+		}
+		if p.Selector != "" {
+			state := playwright.WaitForSelectorStateVisible
+			if p.State == "hidden" {
+				state = playwright.WaitForSelectorStateHidden
+			}
+			err := frame.Locator(p.Selector).First().WaitFor(playwright.LocatorWaitForOptions{
+				State:   state,
+				Timeout: timeout,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("selector %q did not become %s: %w", p.Selector, p.State, err)
+			}
+			code = append(code, fmt.Sprintf("await page.locator('%s').first().waitFor({ state: '%s' });", p.Selector, p.State))
+		}
+		if p.URL != "" {
+			if err := tab.Page().WaitForURL(p.URL, playwright.PageWaitForURLOptions{Timeout: timeout}); err != nil {
+				return nil, fmt.Errorf("URL did not match %q: %w", p.URL, err)
+			}
+			code = append(code, fmt.Sprintf("await page.waitForURL('%s');", p.URL))
+		}
+		return &ToolResult{
+			Code:            code,
+			CaptureSnapshot: true,
+		}, nil
+	},
+}